@@ -0,0 +1,6 @@
+package common
+
+// Opts are the options shared by the contracts.
+type Opts struct {
+	From string
+}
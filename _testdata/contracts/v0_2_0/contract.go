@@ -0,0 +1,18 @@
+package v0_2_0
+
+import "github.com/forta-network/go-merge-types/_testdata/contracts/common"
+
+// Contract is the v0_2_0 contract.
+type Contract struct {
+	opts *common.Opts
+}
+
+// NewContract creates a new contract.
+func NewContract(opts *common.Opts) (*Contract, error) {
+	return &Contract{opts: opts}, nil
+}
+
+// Call calls the contract.
+func (contract *Contract) Call(method string) (string, error) {
+	return method, nil
+}
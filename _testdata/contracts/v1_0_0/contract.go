@@ -0,0 +1,29 @@
+package v1_0_0
+
+import "github.com/forta-network/go-merge-types/_testdata/contracts/common"
+
+// Contract is the v1_0_0 contract.
+type Contract struct {
+	opts *common.Opts
+}
+
+// NewContract creates a new contract.
+func NewContract(opts *common.Opts) (*Contract, error) {
+	return &Contract{opts: opts}, nil
+}
+
+// Call calls the contract.
+func (contract *Contract) Call(method string) (string, error) {
+	return method, nil
+}
+
+// Filterer filters the contract events.
+type Filterer struct{}
+
+// NewFilterer creates a new filterer.
+func NewFilterer() (*Filterer, error) {
+	return &Filterer{}, nil
+}
+
+// Event is a contract event without a constructor.
+type Event struct{}
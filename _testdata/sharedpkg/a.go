@@ -0,0 +1,283 @@
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package sharedpkg
+
+import (
+	import_errors "errors"
+	import_fmt "fmt"
+	import_sync "sync"
+
+	pkg1_1 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg2_2 "github.com/forta-network/go-merge-types/example/pkg2"
+)
+
+// ErrNotImplemented matches the errors from the calls to the methods which are not
+// implemented for the current tag.
+var ErrNotImplemented = import_errors.New("not implemented")
+
+// NotImplementedError is returned from a merged type method if the implementation
+// of the current tag does not have the method.
+type NotImplementedError struct {
+	Type   string
+	Method string
+	Tag    string
+}
+
+func (err *NotImplementedError) Error() string {
+	return import_fmt.Sprintf("%s.%s not implemented (tag=%s)", err.Type, err.Method, err.Tag)
+}
+
+// Is makes the error match ErrNotImplemented.
+func (err *NotImplementedError) Is(target error) bool {
+	return target == ErrNotImplemented
+}
+
+// A is a new type which can multiplex calls to different implementation types.
+type A struct {
+	typ0    *pkg1_1.Impl1
+	typ1    *pkg2_2.Impl2
+	currTag string
+	mu      import_sync.RWMutex
+	unsafe  bool // default: false
+}
+
+// NewA creates a new merged type.
+func NewA(arg1 string, arg2 int, arg2Alt1 int64) (*A, error) {
+	var (
+		mergedType A
+		err        error
+	)
+	mergedType.currTag = "v0.0.1"
+
+	mergedType.typ0, err = pkg1_1.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_1.Impl1: %w", err)
+	}
+
+	mergedType.typ1, err = pkg2_2.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_2.Impl2: %w", err)
+	}
+
+	return &mergedType, nil
+}
+
+// ResolveTagForA finds the source tag which is equal to given tag or has a version range containing it.
+func ResolveTagForA(tag string) (string, bool) {
+	if tag == "v0.0.1" {
+		return tag, true
+	}
+
+	if tag == "v0.0.2" {
+		return tag, true
+	}
+
+	return "", false
+}
+
+// IsKnownTagForA tells if given tag is a known tag.
+func IsKnownTagForA(tag string) bool {
+	_, ok := ResolveTagForA(tag)
+	return ok
+}
+
+// Use sets the used implementation to given tag.
+func (merged *A) Use(tag string) (changed bool) {
+	if !merged.unsafe {
+		merged.mu.Lock()
+		defer merged.mu.Unlock()
+	}
+	// use the default tag if the provided tag is unknown
+	tag, ok := ResolveTagForA(tag)
+	if !ok {
+		tag = "v0.0.1"
+	}
+	changed = merged.currTag != tag
+	merged.currTag = tag
+	return
+}
+
+// Unsafe disables the mutex.
+func (merged *A) Unsafe() {
+	merged.unsafe = true
+}
+
+// Safe enables the mutex.
+func (merged *A) Safe() {
+	merged.unsafe = false
+}
+
+// methodsByTagForA are the supported methods of each tag.
+var methodsByTagForA = map[string][]string{
+	"v0.0.1": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.0.2": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+}
+
+// MethodsForTagForA returns the methods which given tag supports. Unknown tags are
+// treated as the default tag.
+func MethodsForTagForA(tag string) []string {
+	tag, ok := ResolveTagForA(tag)
+	if !ok {
+		tag = "v0.0.1"
+	}
+	return append([]string(nil), methodsByTagForA[tag]...)
+}
+
+// Supports tells if the implementation of the current tag supports given method.
+func (merged *A) Supports(method string) bool {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+	return merged.SupportsForTag(merged.currTag, method)
+}
+
+// SupportsForTag tells if the implementation of given tag supports given method.
+func (merged *A) SupportsForTag(tag, method string) bool {
+	for _, supported := range MethodsForTagForA(tag) {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
+// AFooOutput is a merged return type.
+type AFooOutput struct {
+	A     string
+	B     float32
+	Value *pkg2_2.Int
+}
+
+// Foo multiplexes to different implementations of the method.
+func (merged *A) Foo(arg1 string, arg2 int, arg3 map[string]interface{}) (retVal *AFooOutput, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	retVal = &AFooOutput{}
+
+	if merged.currTag == "v0.0.1" {
+		val, methodErr := merged.typ0.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+	}
+
+	if merged.currTag == "v0.0.2" {
+		val, methodErr := merged.typ1.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "A", Method: "Foo", Tag: merged.currTag}
+	return
+}
+
+// Bar multiplexes to different implementations of the method.
+func (merged *A) Bar(arg1 chan *string) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.1" {
+		merged.typ0.Bar(arg1)
+
+		return
+	}
+
+	panic(&NotImplementedError{Type: "A", Method: "Bar", Tag: merged.currTag})
+}
+
+// SingleReturnVal multiplexes to different implementations of the method.
+func (merged *A) SingleReturnVal(arg string) (retVal int, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.1" {
+		val, methodErr := merged.typ0.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "v0.0.2" {
+		val, methodErr := merged.typ1.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "A", Method: "SingleReturnVal", Tag: merged.currTag}
+	return
+}
+
+// NoReturnVal multiplexes to different implementations of the method.
+func (merged *A) NoReturnVal() (err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.2" {
+		methodErr := merged.typ1.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "A", Method: "NoReturnVal", Tag: merged.currTag}
+	return
+}
+
+// Lookup multiplexes to different implementations of the method.
+func (merged *A) Lookup(key string) (retVal int, retVal1 bool) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.2" {
+		val, val1 := merged.typ1.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	panic(&NotImplementedError{Type: "A", Method: "Lookup", Tag: merged.currTag})
+}
@@ -0,0 +1,262 @@
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// maxOutputErrors limits the reported errors of the output.
+const maxOutputErrors = 10
+
+// checkOutput formats and type-checks the generated files together with the rest of the
+// output package, so that the broken output is reported before it is written.
+func checkOutput(config *MergeConfig, sourceImpls []*SourceImplementation, code []byte) error {
+	files := append([]*File{{Path: config.Output.File, Code: code}}, config.Output.ExtraFiles...)
+	files = append(files, config.Output.PackageFiles...)
+
+	// the generated files replace the existing ones while loading
+	overlay := make(map[string][]byte)
+	for _, file := range files {
+		if _, err := format.Source(file.Code); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidOutput, file.Path, err)
+		}
+		absPath, err := filepath.Abs(filepath.Join(config.BaseDir, file.Path))
+		if err != nil {
+			return err
+		}
+		overlay[absPath] = file.Code
+	}
+
+	// the output dir may not exist yet
+	outDir := filepath.Dir(filepath.Join(config.BaseDir, config.Output.File))
+	pattern, err := filepath.Rel(config.BaseDir, outDir)
+	if err != nil {
+		return err
+	}
+	pattern = "./" + filepath.ToSlash(pattern)
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: config.BaseDir, Overlay: overlay}, pattern)
+	if err != nil {
+		return fmt.Errorf("failed to load the output package: %w", err)
+	}
+	if len(pkgs) == 0 {
+		return fmt.Errorf("%w: %s", ErrPackageNotFound, outDir)
+	}
+	pkg := pkgs[0]
+
+	var errs []error
+	for _, pkgErr := range pkg.Errors {
+		// the compiler errors of the export data repeat the type errors at the temporary
+		// overlay paths
+		if len(pkg.TypeErrors) > 0 && pkgErr.Kind == packages.ListError {
+			continue
+		}
+		if len(errs) == maxOutputErrors {
+			errs = append(errs, fmt.Errorf("%w: too many errors", ErrInvalidOutput))
+			break
+		}
+		errs = append(errs, mapOutputError(sourceImpls, pkg, pkgErr))
+	}
+	return errors.Join(errs...)
+}
+
+// sharesErrors tells if another file of the output package declares the not implemented
+// errors already, so that the targets which are generated into the same package by separate
+// configs do not redeclare them.
+func sharesErrors(config *MergeConfig) (bool, error) {
+	// the files of this target are generated again
+	ownFiles := make(map[string]bool)
+	for _, filePath := range []string{config.Output.File, config.Output.Interface.File, config.Output.Fake.File} {
+		if len(filePath) == 0 {
+			continue
+		}
+		absPath, err := filepath.Abs(filepath.Join(config.BaseDir, filePath))
+		if err != nil {
+			return false, err
+		}
+		ownFiles[absPath] = true
+	}
+
+	// the files of the earlier targets replace the ones on disk
+	files := make(map[string][]byte)
+	outDir := filepath.Dir(filepath.Join(config.BaseDir, config.Output.File))
+	filePaths, err := filepath.Glob(filepath.Join(outDir, "*.go"))
+	if err != nil {
+		return false, err
+	}
+	for _, filePath := range filePaths {
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			return false, err
+		}
+		if !strings.HasSuffix(absPath, "_test.go") {
+			files[absPath] = nil
+		}
+	}
+	for _, file := range config.Output.PackageFiles {
+		absPath, err := filepath.Abs(filepath.Join(config.BaseDir, file.Path))
+		if err != nil {
+			return false, err
+		}
+		files[absPath] = file.Code
+	}
+
+	fset := token.NewFileSet()
+	for absPath, code := range files {
+		if ownFiles[absPath] {
+			continue
+		}
+		// the files on disk are read by the parser
+		var src any
+		if code != nil {
+			src = code
+		}
+		// the broken files are reported by the output check
+		file, err := parser.ParseFile(fset, absPath, src, parser.SkipObjectResolution)
+		if err != nil || file.Name.Name != config.Output.Package {
+			continue
+		}
+		if declares(file, "ErrNotImplemented") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// declares tells if the file declares given name at the package level.
+func declares(file *ast.File, name string) bool {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			switch spec := spec.(type) {
+			case *ast.ValueSpec:
+				for _, ident := range spec.Names {
+					if ident.Name == name {
+						return true
+					}
+				}
+			case *ast.TypeSpec:
+				if spec.Name.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+var dispatchFieldRegexp = regexp.MustCompile(`^typ(\d+)$`)
+
+// mapOutputError maps the error to the source method which the erroneous code dispatches
+// to, if there is one.
+func mapOutputError(sourceImpls []*SourceImplementation, pkg *packages.Package, pkgErr packages.Error) error {
+	var path []ast.Node
+	if pos, ok := findErrorPos(pkg, pkgErr); ok {
+		for _, file := range pkg.Syntax {
+			if file.FileStart <= pos && pos <= file.FileEnd {
+				path, _ = astutil.PathEnclosingInterval(file, pos, pos)
+				break
+			}
+		}
+	}
+
+	for _, node := range path {
+		switch node := node.(type) {
+		case *ast.IfStmt, *ast.CaseClause, *ast.AssignStmt:
+			if sourceIndex, name, ok := findDispatch(node); ok && sourceIndex < len(sourceImpls) {
+				sourceImpl := sourceImpls[sourceIndex]
+				pos := sourceImpl.Constructor.Pos()
+				for _, method := range sourceImpl.Methods {
+					if method.Name() == name {
+						pos = method.Pos()
+					}
+				}
+				return newSourceError(sourceIndex, sourceImpl.Package, name, pos, fmt.Errorf("%w: %v", ErrInvalidOutput, pkgErr))
+			}
+
+		case *ast.FuncDecl:
+			return fmt.Errorf("%w: %s: %v", ErrInvalidOutput, node.Name.Name, pkgErr)
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrInvalidOutput, pkgErr)
+}
+
+// findErrorPos finds the position of the type error.
+func findErrorPos(pkg *packages.Package, pkgErr packages.Error) (token.Pos, bool) {
+	for _, typeErr := range pkg.TypeErrors {
+		if typeErr.Msg == pkgErr.Msg && typeErr.Fset.Position(typeErr.Pos).String() == pkgErr.Pos {
+			return typeErr.Pos, true
+		}
+	}
+	return token.NoPos, false
+}
+
+// findDispatch finds the call to a source method or constructor in given node.
+func findDispatch(node ast.Node) (sourceIndex int, name string, found bool) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		if found {
+			return false
+		}
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			// mergedType.typN, err = pkg.NewType(...)
+			if len(node.Lhs) == 0 || len(node.Rhs) != 1 {
+				return true
+			}
+			sel, ok := node.Lhs[0].(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			matches := dispatchFieldRegexp.FindStringSubmatch(sel.Sel.Name)
+			call, ok := node.Rhs[0].(*ast.CallExpr)
+			if len(matches) == 0 || !ok {
+				return true
+			}
+			if constructor := callName(call); len(constructor) > 0 {
+				sourceIndex, _ = strconv.Atoi(matches[1])
+				name, found = constructor, true
+			}
+
+		case *ast.SelectorExpr:
+			// recv.typN.Method
+			inner, ok := node.X.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if matches := dispatchFieldRegexp.FindStringSubmatch(inner.Sel.Name); len(matches) > 0 {
+				sourceIndex, _ = strconv.Atoi(matches[1])
+				name, found = node.Sel.Name, true
+			}
+		}
+		return !found
+	})
+	return
+}
+
+func callName(call *ast.CallExpr) string {
+	fun := call.Fun
+	// generic constructors are instantiated explicitly
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+	if sel, ok := fun.(*ast.SelectorExpr); ok {
+		return sel.Sel.Name
+	}
+	return ""
+}
@@ -2,9 +2,10 @@ package main
 
 import (
 	"fmt"
-	"io/ioutil"
 	"log"
+	"os"
 	"path"
+	"strings"
 
 	"github.com/forta-network/go-merge-types"
 	"github.com/forta-network/go-merge-types/utils"
@@ -18,29 +19,150 @@ var mainCmd = &cobra.Command{
 	SilenceUsage: true,
 }
 
+var checkCmd = &cobra.Command{
+	Use:          "check",
+	Short:        "Check that the generated files are up to date",
+	Long:         `Generate the code in memory, print a diff of the files which differ from the generated code and fail if there are any`,
+	Run:          handleCheck,
+	SilenceUsage: true,
+}
+
+var schemaCmd = &cobra.Command{
+	Use:          "schema",
+	Short:        "Print the JSON Schema of the config file",
+	Run:          handleSchema,
+	SilenceUsage: true,
+}
+
+var initCmd = &cobra.Command{
+	Use:          "init",
+	Short:        "Write a starter config from a dir of versioned packages",
+	Long:         `Scan the versioned packages in a dir, find the type with a New<Type> constructor in each package, infer the tags from the dir names and write a starter config`,
+	Run:          handleInit,
+	SilenceUsage: true,
+}
+
 var (
 	flagConfigPath *string
 	flagVerbose    *bool
+	flagDryRun     *bool
+	flagOut        *string
+
+	scaffoldOpts merge.ScaffoldOptions
 )
 
 func handleMain(cmd *cobra.Command, args []string) {
-	config, b, err := merge.Run(*flagConfigPath)
+	targets := runAll()
+
+	if len(*flagOut) > 0 && len(targets) > 1 {
+		log.Fatalf("--out cannot be used with %d merge targets", len(targets))
+	}
+	// the extra files cannot be printed together with the merged type
+	if *flagOut == "-" && len(targets) == 1 {
+		if files := targets[0].Files(); len(files) > 1 {
+			var extraFiles []string
+			for _, file := range files[1:] {
+				extraFiles = append(extraFiles, file.Path)
+			}
+			log.Fatalf("--out - cannot be used with the extra files: %s", strings.Join(extraFiles, ", "))
+		}
+	}
+
+	for _, target := range targets {
+		// the code is printed only once when writing to stdout
+		if *flagVerbose && *flagOut != "-" {
+			fmt.Println(string(target.Code))
+		}
+
+		// print the merged type only: it can be piped to other tools
+		if *flagOut == "-" {
+			fmt.Print(string(target.Code))
+			continue
+		}
+
+		for i, file := range target.Files() {
+			filePath := utils.RelativePath(*flagConfigPath, file.Path)
+			if i == 0 && len(*flagOut) > 0 {
+				filePath = *flagOut
+			}
+			if *flagDryRun {
+				log.Printf("would write %s", filePath)
+				continue
+			}
+			if err := os.WriteFile(filePath, file.Code, 0644); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
+
+func handleCheck(cmd *cobra.Command, args []string) {
+	targets := runAll()
+
+	var outdated bool
+	for _, target := range targets {
+		diff, err := target.Diff()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(diff) > 0 {
+			fmt.Print(diff)
+			outdated = true
+		}
+	}
+	if outdated {
+		log.Fatalf("generated code is out of date: run gomergetypes --config %s", *flagConfigPath)
+	}
+}
+
+func handleSchema(cmd *cobra.Command, args []string) {
+	schema, err := merge.Schema()
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Print(string(schema))
+}
 
-	if *flagVerbose {
-		fmt.Println(string(b))
+func handleInit(cmd *cobra.Command, args []string) {
+	if _, err := os.Stat(*flagConfigPath); err == nil {
+		log.Fatalf("config file %s already exists", *flagConfigPath)
 	}
+	b, err := merge.Scaffold(*flagConfigPath, scaffoldOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*flagConfigPath, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %s", *flagConfigPath)
+}
 
-	if err := ioutil.WriteFile(utils.RelativePath(*flagConfigPath, config.Output.File), b, 0755); err != nil {
+func runAll() []*merge.Target {
+	targets, err := merge.RunAll(*flagConfigPath)
+	for _, target := range targets {
+		for _, warning := range target.Config.Warnings {
+			log.Printf("warning: %v", warning)
+		}
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
+	return targets
 }
 
 func main() {
-	flagConfigPath = mainCmd.Flags().String("config", "gomergetypes.yml", "config file path")
+	flagConfigPath = mainCmd.PersistentFlags().String("config", "gomergetypes.yml", "config file path")
 	flagVerbose = mainCmd.Flags().BoolP("verbose", "v", false, "verbose output")
+	flagDryRun = mainCmd.Flags().Bool("dry-run", false, "generate without writing the files")
+	flagOut = mainCmd.Flags().String("out", "", "output file path instead of the one in the config, or - to print the merged type to stdout without writing any files")
+	initCmd.Flags().StringVar(&scaffoldOpts.Dir, "dir", ".", "parent dir of the versioned packages")
+	initCmd.Flags().StringVar(&scaffoldOpts.TagPattern, "tag-pattern", merge.DefaultTagPattern, "pattern of the versioned dir names: the captured parts make the tag")
+	initCmd.Flags().StringVar(&scaffoldOpts.TagTemplate, "tag-template", "", "template of the tag with the captured parts like v$1.$2.0 (default: the parts joined with dots)")
+	initCmd.Flags().StringVar(&scaffoldOpts.Type, "type", "", "implementation type, if the packages have multiple types with a constructor")
+	initCmd.Flags().StringVar(&scaffoldOpts.OutputType, "output-type", "", "merged type name (default: the latest implementation type)")
+	initCmd.Flags().StringVar(&scaffoldOpts.OutputPackage, "output-package", "merged", "output package name")
+	initCmd.Flags().StringVar(&scaffoldOpts.OutputFile, "output-file", "", "output file path relative to the config (default: ./<output package>/out.go)")
+	mainCmd.AddCommand(checkCmd, schemaCmd, initCmd)
 	if err := mainCmd.Execute(); err != nil {
 		log.Fatal(err)
 	}
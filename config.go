@@ -2,18 +2,43 @@ package merge
 
 import (
 	"github.com/forta-network/go-merge-types/rewrite"
+	"github.com/forta-network/go-merge-types/semver"
 )
 
+// ConfigFile is the config file which can contain multiple merge targets.
+type ConfigFile struct {
+	MergeConfig `yaml:",inline"`
+	Targets     []*MergeConfig `yaml:"targets"`
+}
+
 type MergeConfig struct {
 	Sources []*Source `yaml:"sources"`
 	Output  Output    `yaml:"output"`
+	BaseDir string    `yaml:"-"`
+
+	// Warnings contains the problems which did not stop the generation.
+	Warnings []error `yaml:"-"`
+}
+
+// Target is a generated merge target.
+type Target struct {
+	Config *MergeConfig
+	Code   []byte
 }
 
 type Source struct {
-	Type     string   `yaml:"type"`
-	Tag      string   `yaml:"tag"`
-	Package  Package  `yaml:"package"`
+	Type    string  `yaml:"type"`
+	Tag     string  `yaml:"tag"`
+	Range   string  `yaml:"range"`
+	Package Package `yaml:"package"`
+	// TypeArgs instantiate a generic implementation type, e.g. ["string", "*big.Int"].
+	TypeArgs []string `yaml:"typeArgs"`
 	InitArgs []*Field `yaml:"-"`
+
+	ImplType            string `yaml:"-"` // pointer or value type as returned from the constructor
+	ConstructorTypeArgs string `yaml:"-"` // explicit instantiation of a generic constructor
+	// RangeSets are the comparison sets of the range, checked inline by the output.
+	RangeSets [][]semver.Comparison `yaml:"-"`
 }
 
 type Package struct {
@@ -28,17 +53,89 @@ type Output struct {
 	File       string           `yaml:"file"`
 	Rewrite    rewrite.Rewriter `yaml:"rewrite"`
 	DefaultTag string           `yaml:"defaultTag"`
+	Interface  Interface        `yaml:"interface"`
+	Fake       Fake             `yaml:"fake"`
+	Fallback   Fallback         `yaml:"fallback"`
+	View       View             `yaml:"view"`
+	NoError    NoError          `yaml:"noError"`
+	Implements Implements       `yaml:"implements"`
+	// Atomic switches the tag atomically instead of using a mutex.
+	Atomic bool `yaml:"atomic"`
+	// IndexDispatch resolves the tag to an index once and dispatches the calls with a switch.
+	IndexDispatch bool `yaml:"indexDispatch"`
+
+	KnownTags []string `yaml:"-"`
+	HasRanges bool     `yaml:"-"`
+	// SharedErrors tells that another file of the output package declares the errors.
+	SharedErrors bool      `yaml:"-"`
+	InitArgs     []*Field  `yaml:"-"`
+	Methods      []*Method `yaml:"-"`
+	// TagMethods are the supported methods of each tag.
+	TagMethods []*TagMethods `yaml:"-"`
+	Imports    []string      `yaml:"-"`
+
+	// ExtraFiles are generated in addition to the output file.
+	ExtraFiles []*File `yaml:"-"`
+	// PackageFiles are generated for the earlier targets of the output package and are
+	// type-checked together with the output.
+	PackageFiles []*File `yaml:"-"`
+}
+
+// Interface is the optional interface generated for the merged type.
+type Interface struct {
+	Name string `yaml:"name"`
+	// File is optional and the interface is generated in the output file if not specified.
+	File string `yaml:"file"`
+}
+
+// Fake is the optional fake implementation generated for the merged type.
+type Fake struct {
+	Name string `yaml:"name"`
+	// File is optional and the fake is generated in the output file if not specified.
+	File string `yaml:"file"`
+}
 
-	KnownTags []string  `yaml:"-"`
-	InitArgs  []*Field  `yaml:"-"`
-	Methods   []*Method `yaml:"-"`
-	Imports   []string  `yaml:"-"`
+// View is the optional view type which calls the implementation of a fixed tag.
+type View struct {
+	Name string `yaml:"name"`
+}
+
+// Implements names an existing interface which the output type should implement.
+type Implements struct {
+	ImportPath string `yaml:"importPath"`
+	Name       string `yaml:"name"`
+
+	TypeName string `yaml:"-"` // as referred from the output
+}
+
+// NoError keeps the methods without an error when no variation returns an error.
+type NoError struct {
+	// Policy handles the not implemented calls: "panic" or "lastError".
+	Policy string `yaml:"policy"`
+}
+
+// Fallback decides which implementation to use when the source of the current tag
+// lacks a method. The method lists are checked before the policy.
+type Fallback struct {
+	Policy string `yaml:"policy"`
+	// Methods are the ordered fallback tags of the methods, by the method names in the sources.
+	Methods map[string][]string `yaml:"methods"`
+
+	methodPos map[string]configPos
+}
+
+// File is a generated file.
+type File struct {
+	Path string
+	Code []byte
 }
 
 type Field struct {
 	SourceIndex int
 	Name        string
 	Type        string
+	Variadic    bool // forwarded as name...
+	Ellipsis    bool // declared as name ...T
 }
 
 type ReturnType struct {
@@ -53,15 +150,32 @@ type Method struct {
 	ReturnType   ReturnType
 	NoReturn     bool
 	SingleReturn bool
+	MultiReturn  bool // all variations return the same values
+	NoError      bool // no variation returns an error
+	// NoErrorPolicy handles the not implemented calls if the method does not return an error.
+	NoErrorPolicy string
+	Implements    bool // shaped after the target interface
+}
+
+// TagMethods are the methods which a tag supports.
+type TagMethods struct {
+	Tag     string
+	Methods []string
 }
 
 type Variation struct {
 	Name                string
 	SourceIndex         int
 	Tag                 string
+	TagIndex            int
 	Args                []*Field
 	ReturnedFields      []*Field
 	MergeReturnedStruct bool
+	MultiReturn         bool
+	Values              []string // names of the returned values at the call site
+	ValueTypes          []string
 	NoReturn            bool
+	NoError             bool
 	OnlyError           bool
+	Fallback            bool
 }
@@ -0,0 +1,46 @@
+package merge
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Files returns the generated files of the target. The paths are relative to the config file.
+func (target *Target) Files() []*File {
+	files := []*File{{Path: target.Config.Output.File, Code: target.Code}}
+	return append(files, target.Config.Output.ExtraFiles...)
+}
+
+// Diff compares the generated files with the files on disk and returns a unified diff
+// of the files which are out of date. A missing file is compared as an empty file.
+func (target *Target) Diff() (string, error) {
+	var diffs []string
+	for _, file := range target.Files() {
+		filePath := path.Join(target.Config.BaseDir, file.Path)
+		current, err := os.ReadFile(filePath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		if bytes.Equal(current, file.Code) {
+			continue
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(current)),
+			B:        difflib.SplitLines(string(file.Code)),
+			FromFile: filePath,
+			ToFile:   filePath + " (generated)",
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		diffs = append(diffs, diff)
+	}
+	return strings.Join(diffs, ""), nil
+}
@@ -0,0 +1,73 @@
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	ErrPackageNotFound        = errors.New("package not found")
+	ErrImplementationNotFound = errors.New("implementation not found")
+	ErrConstructorNotFound    = errors.New("constructor not found")
+	ErrUnsupportedConstructor = errors.New("unsupported constructor: should return the implementation and an error")
+	ErrInvalidTypeArgs        = errors.New("invalid type arguments")
+	ErrUnsupportedReturn      = errors.New("unsupported return list")
+	ErrInvalidRange           = errors.New("invalid version range")
+	ErrOverlappingRanges      = errors.New("overlapping version ranges")
+	ErrInvalidFallback        = errors.New("invalid fallback")
+	ErrInvalidNoError         = errors.New("invalid no error config")
+	ErrInterfaceNotFound      = errors.New("interface not found")
+	ErrInterfaceNotSatisfied  = errors.New("interface not satisfied")
+	ErrIncompatibleVariation  = errors.New("incompatible with the interface method")
+	ErrInvalidOutput          = errors.New("invalid output")
+	ErrUnknownConfigField     = errors.New("unknown config field")
+	ErrMissingConfigField     = errors.New("missing config field")
+	ErrDuplicateTag           = errors.New("duplicate tag")
+	ErrUnknownDefaultTag      = errors.New("default tag is not a source tag")
+	ErrInvalidRewrite         = errors.New("invalid rewrite rule")
+	ErrInvalidTagPattern      = errors.New("invalid tag pattern")
+	ErrMultipleCandidates     = errors.New("multiple implementation candidates: specify the type")
+)
+
+// SourceError is an error which occurred while processing a source.
+type SourceError struct {
+	SourceIndex int
+	Package     string
+	Name        string
+	Position    token.Position
+	Err         error
+}
+
+func newSourceError(sourceIndex int, pkg *packages.Package, name string, pos token.Pos, err error) *SourceError {
+	srcErr := &SourceError{
+		SourceIndex: sourceIndex,
+		Package:     pkg.PkgPath,
+		Name:        name,
+		Err:         err,
+	}
+	if pkg.Fset != nil {
+		srcErr.Position = pkg.Fset.Position(pos)
+	}
+	return srcErr
+}
+
+func (srcErr *SourceError) Error() string {
+	var b strings.Builder
+	if srcErr.Position.IsValid() {
+		b.WriteString(srcErr.Position.String() + ": ")
+	}
+	fmt.Fprintf(&b, "source %d (%s): ", srcErr.SourceIndex, srcErr.Package)
+	if len(srcErr.Name) > 0 {
+		b.WriteString(srcErr.Name + ": ")
+	}
+	b.WriteString(srcErr.Err.Error())
+	return b.String()
+}
+
+func (srcErr *SourceError) Unwrap() error {
+	return srcErr.Err
+}
@@ -0,0 +1,19 @@
+package api
+
+import "math/big"
+
+// Impl is a hand-written interface which the merged type implements.
+type Impl interface {
+	Use(tag string) (changed bool)
+	Foo(arg2 int, arg3 *big.Int) (*big.Int, error)
+	SingleReturnVal(arg string) (int, error)
+	NoReturnVal(arg int) error
+	Lookup(key string) (value int, ok bool)
+}
+
+// Extended cannot be implemented by merging the example sources.
+type Extended interface {
+	Impl
+	Baz() error
+	Bar(arg1 chan *string) (int, error)
+}
@@ -0,0 +1,381 @@
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package apipkg
+
+import (
+	import_errors "errors"
+	import_fmt "fmt"
+	"math/big"
+	import_sync "sync"
+
+	"github.com/forta-network/go-merge-types/example/api"
+	pkg1 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg2 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg3 "github.com/forta-network/go-merge-types/example/pkg3"
+)
+
+// ErrNotImplemented matches the errors from the calls to the methods which are not
+// implemented for the current tag.
+var ErrNotImplemented = import_errors.New("not implemented")
+
+// NotImplementedError is returned from a merged type method if the implementation
+// of the current tag does not have the method.
+type NotImplementedError struct {
+	Type   string
+	Method string
+	Tag    string
+}
+
+func (err *NotImplementedError) Error() string {
+	return import_fmt.Sprintf("%s.%s not implemented (tag=%s)", err.Type, err.Method, err.Tag)
+}
+
+// Is makes the error match ErrNotImplemented.
+func (err *NotImplementedError) Is(target error) bool {
+	return target == ErrNotImplemented
+}
+
+// Impl is a new type which can multiplex calls to different implementation types.
+type Impl struct {
+	typ0    *pkg1.Impl1
+	typ1    *pkg2.Impl2
+	typ2    *pkg3.Impl3
+	currTag string
+	mu      import_sync.RWMutex
+	unsafe  bool // default: false
+}
+
+var _ api.Impl = &Impl{}
+
+// NewImpl creates a new merged type.
+func NewImpl(arg1 string, arg2 int, arg2Alt1 int64, arg3 *import_sync.WaitGroup, arg4 *pkg3.Foo) (*Impl, error) {
+	var (
+		mergedType Impl
+		err        error
+	)
+	mergedType.currTag = "v0.0.3"
+
+	mergedType.typ0, err = pkg1.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1.Impl1: %w", err)
+	}
+
+	mergedType.typ1, err = pkg2.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2.Impl2: %w", err)
+	}
+
+	mergedType.typ2, err = pkg3.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3.Impl3: %w", err)
+	}
+
+	return &mergedType, nil
+}
+
+// ResolveTagForImpl finds the source tag which is equal to given tag or has a version range containing it.
+func ResolveTagForImpl(tag string) (string, bool) {
+	if tag == "v0.0.1" {
+		return tag, true
+	}
+
+	if tag == "v0.0.2" {
+		return tag, true
+	}
+
+	if tag == "v0.0.3" {
+		return tag, true
+	}
+
+	return "", false
+}
+
+// IsKnownTagForImpl tells if given tag is a known tag.
+func IsKnownTagForImpl(tag string) bool {
+	_, ok := ResolveTagForImpl(tag)
+	return ok
+}
+
+// Use sets the used implementation to given tag.
+func (merged *Impl) Use(tag string) (changed bool) {
+	if !merged.unsafe {
+		merged.mu.Lock()
+		defer merged.mu.Unlock()
+	}
+	// use the default tag if the provided tag is unknown
+	tag, ok := ResolveTagForImpl(tag)
+	if !ok {
+		tag = "v0.0.3"
+	}
+	changed = merged.currTag != tag
+	merged.currTag = tag
+	return
+}
+
+// Unsafe disables the mutex.
+func (merged *Impl) Unsafe() {
+	merged.unsafe = true
+}
+
+// Safe enables the mutex.
+func (merged *Impl) Safe() {
+	merged.unsafe = false
+}
+
+// methodsByTagForImpl are the supported methods of each tag.
+var methodsByTagForImpl = map[string][]string{
+	"v0.0.1": {"Bar", "SingleReturnVal"},
+	"v0.0.2": {"SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.0.3": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+}
+
+// MethodsForTagForImpl returns the methods which given tag supports. Unknown tags are
+// treated as the default tag.
+func MethodsForTagForImpl(tag string) []string {
+	tag, ok := ResolveTagForImpl(tag)
+	if !ok {
+		tag = "v0.0.3"
+	}
+	return append([]string(nil), methodsByTagForImpl[tag]...)
+}
+
+// Supports tells if the implementation of the current tag supports given method.
+func (merged *Impl) Supports(method string) bool {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+	return merged.SupportsForTag(merged.currTag, method)
+}
+
+// SupportsForTag tells if the implementation of given tag supports given method.
+func (merged *Impl) SupportsForTag(tag, method string) bool {
+	for _, supported := range MethodsForTagForImpl(tag) {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Foo multiplexes to different implementations of the method.
+func (merged *Impl) Foo(arg2 int, arg3 *big.Int) (retVal *big.Int, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.3" {
+		val, methodErr := merged.typ2.Foo(arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Foo", Tag: merged.currTag}
+	return
+}
+
+// Bar multiplexes to different implementations of the method.
+func (merged *Impl) Bar(arg1 chan *string, arg1Alt2 map[string]interface{}) (err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.1" {
+		merged.typ0.Bar(arg1)
+
+		return
+	}
+
+	if merged.currTag == "v0.0.3" {
+		methodErr := merged.typ2.Bar(arg1Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Bar", Tag: merged.currTag}
+	return
+}
+
+// SingleReturnVal multiplexes to different implementations of the method.
+func (merged *Impl) SingleReturnVal(arg string) (retVal int, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.1" {
+		val, methodErr := merged.typ0.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "v0.0.2" {
+		val, methodErr := merged.typ1.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "SingleReturnVal", Tag: merged.currTag}
+	return
+}
+
+// NoReturnVal multiplexes to different implementations of the method.
+func (merged *Impl) NoReturnVal(arg int) (err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.2" {
+		methodErr := merged.typ1.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if merged.currTag == "v0.0.3" {
+		methodErr := merged.typ2.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "NoReturnVal", Tag: merged.currTag}
+	return
+}
+
+// Lookup multiplexes to different implementations of the method.
+func (merged *Impl) Lookup(key string) (retVal int, retVal1 bool) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.2" {
+		val, val1 := merged.typ1.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	panic(&NotImplementedError{Type: "Impl", Method: "Lookup", Tag: merged.currTag})
+}
+
+// ArrayMethod multiplexes to different implementations of the method.
+func (merged *Impl) ArrayMethod(sli []*pkg3.Something, arr [32]*pkg3.Something) (err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.3" {
+		methodErr := merged.typ2.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "ArrayMethod", Tag: merged.currTag}
+	return
+}
+
+// ChanMethod multiplexes to different implementations of the method.
+func (merged *Impl) ChanMethod(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.Something, chan3 chan<- *pkg3.Something) (err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.3" {
+		methodErr := merged.typ2.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "ChanMethod", Tag: merged.currTag}
+	return
+}
+
+// MapMethod multiplexes to different implementations of the method.
+func (merged *Impl) MapMethod(m map[string]*pkg3.Something) (err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.3" {
+		methodErr := merged.typ2.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "MapMethod", Tag: merged.currTag}
+	return
+}
+
+// FooBarBaz multiplexes to different implementations of the method.
+func (merged *Impl) FooBarBaz() {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.3" {
+		merged.typ2.FooBarBaz()
+
+		return
+	}
+
+	panic(&NotImplementedError{Type: "Impl", Method: "FooBarBaz", Tag: merged.currTag})
+}
@@ -0,0 +1,516 @@
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package atomicpkg
+
+import (
+	import_errors "errors"
+	import_fmt "fmt"
+	"math/big"
+	import_sync "sync"
+	import_atomic "sync/atomic"
+
+	pkg1 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg2 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg3 "github.com/forta-network/go-merge-types/example/pkg3"
+	pkg4 "github.com/forta-network/go-merge-types/example/pkg4"
+)
+
+// ErrNotImplemented matches the errors from the calls to the methods which are not
+// implemented for the current tag.
+var ErrNotImplemented = import_errors.New("not implemented")
+
+// NotImplementedError is returned from a merged type method if the implementation
+// of the current tag does not have the method.
+type NotImplementedError struct {
+	Type   string
+	Method string
+	Tag    string
+}
+
+func (err *NotImplementedError) Error() string {
+	return import_fmt.Sprintf("%s.%s not implemented (tag=%s)", err.Type, err.Method, err.Tag)
+}
+
+// Is makes the error match ErrNotImplemented.
+func (err *NotImplementedError) Is(target error) bool {
+	return target == ErrNotImplemented
+}
+
+// Impl is a new type which can multiplex calls to different implementation types.
+type Impl struct {
+	typ0    *pkg1.Impl1
+	typ1    *pkg2.Impl2
+	typ2    *pkg3.Impl3
+	typ3    pkg4.Impl4[*big.Int]
+	currTag import_atomic.Pointer[string]
+}
+
+// NewImpl creates a new merged type.
+func NewImpl(arg1 string, arg2 int, arg2Alt1 int64, arg3 *import_sync.WaitGroup, arg4 *pkg3.Foo, opts ...string) (*Impl, error) {
+	var (
+		mergedType Impl
+		err        error
+	)
+	defaultTag := "v0.0.3"
+	mergedType.currTag.Store(&defaultTag)
+
+	mergedType.typ0, err = pkg1.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1.Impl1: %w", err)
+	}
+
+	mergedType.typ1, err = pkg2.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2.Impl2: %w", err)
+	}
+
+	mergedType.typ2, err = pkg3.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3.Impl3: %w", err)
+	}
+
+	mergedType.typ3, err = pkg4.NewImpl4[*big.Int](arg2, opts...)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg4.Impl4: %w", err)
+	}
+
+	return &mergedType, nil
+}
+
+// ResolveTagForImpl finds the source tag which is equal to given tag or has a version range containing it.
+func ResolveTagForImpl(tag string) (string, bool) {
+	if tag == "v0.0.1" {
+		return tag, true
+	}
+
+	if tag == "v0.0.2" {
+		return tag, true
+	}
+
+	if tag == "v0.0.3" {
+		return tag, true
+	}
+
+	if tag == "v0.0.4" {
+		return tag, true
+	}
+
+	return "", false
+}
+
+// IsKnownTagForImpl tells if given tag is a known tag.
+func IsKnownTagForImpl(tag string) bool {
+	_, ok := ResolveTagForImpl(tag)
+	return ok
+}
+
+// Use sets the used implementation to given tag.
+func (merged *Impl) Use(tag string) (changed bool) {
+	// use the default tag if the provided tag is unknown
+	tag, ok := ResolveTagForImpl(tag)
+	if !ok {
+		tag = "v0.0.3"
+	}
+	return *merged.currTag.Swap(&tag) != tag
+}
+
+// Unsafe has no effect since the tag is switched atomically.
+func (merged *Impl) Unsafe() {}
+
+// Safe has no effect since the tag is switched atomically.
+func (merged *Impl) Safe() {}
+
+// methodsByTagForImpl are the supported methods of each tag.
+var methodsByTagForImpl = map[string][]string{
+	"v0.0.1": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.0.2": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.0.3": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+	"v0.0.4": {"Foo", "NoReturnVal", "Lookup", "Limit", "Sum", "Store", "Append"},
+}
+
+// MethodsForTagForImpl returns the methods which given tag supports. Unknown tags are
+// treated as the default tag.
+func MethodsForTagForImpl(tag string) []string {
+	tag, ok := ResolveTagForImpl(tag)
+	if !ok {
+		tag = "v0.0.3"
+	}
+	return append([]string(nil), methodsByTagForImpl[tag]...)
+}
+
+// Supports tells if the implementation of the current tag supports given method.
+func (merged *Impl) Supports(method string) bool {
+	return merged.SupportsForTag(*merged.currTag.Load(), method)
+}
+
+// SupportsForTag tells if the implementation of given tag supports given method.
+func (merged *Impl) SupportsForTag(tag, method string) bool {
+	for _, supported := range MethodsForTagForImpl(tag) {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
+// FooOutput is a merged return type.
+type FooOutput struct {
+	A         string
+	B         float32
+	Value     *pkg2.Int
+	ValueAlt3 *big.Int
+}
+
+// Foo multiplexes to different implementations of the method.
+func (merged *Impl) Foo(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (retVal *FooOutput, err error) {
+	currTag := *merged.currTag.Load()
+
+	retVal = &FooOutput{}
+
+	if currTag == "v0.0.1" {
+		val, methodErr := merged.typ0.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+	}
+
+	if currTag == "v0.0.2" {
+		val, methodErr := merged.typ1.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+	}
+
+	if currTag == "v0.0.3" {
+		val, methodErr := merged.typ2.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+	}
+
+	if currTag == "v0.0.4" {
+		val, methodErr := merged.typ3.Foo(arg2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Foo", Tag: currTag}
+	return
+}
+
+// Bar multiplexes to different implementations of the method.
+func (merged *Impl) Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.1" {
+		merged.typ0.Bar(arg1)
+
+		return
+	}
+
+	if currTag == "v0.0.3" {
+		methodErr := merged.typ2.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Bar", Tag: currTag}
+	return
+}
+
+// SingleReturnVal multiplexes to different implementations of the method.
+func (merged *Impl) SingleReturnVal(arg string) (retVal int, err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.1" {
+		val, methodErr := merged.typ0.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if currTag == "v0.0.2" {
+		val, methodErr := merged.typ1.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "SingleReturnVal", Tag: currTag}
+	return
+}
+
+// NoReturnVal multiplexes to different implementations of the method.
+func (merged *Impl) NoReturnVal(arg int) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.2" {
+		methodErr := merged.typ1.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.0.3" {
+		methodErr := merged.typ2.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.0.4" {
+		methodErr := merged.typ3.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "NoReturnVal", Tag: currTag}
+	return
+}
+
+// LookupOutput is a merged return type.
+type LookupOutput struct {
+	Value     int
+	Value1    bool
+	ValueAlt5 *big.Int
+}
+
+// Lookup multiplexes to different implementations of the method.
+func (merged *Impl) Lookup(key string) (retVal *LookupOutput, err error) {
+	currTag := *merged.currTag.Load()
+
+	retVal = &LookupOutput{}
+
+	if currTag == "v0.0.2" {
+		val, val1 := merged.typ1.Lookup(key)
+
+		retVal.Value = val
+		retVal.Value1 = val1
+
+		return
+	}
+
+	if currTag == "v0.0.4" {
+		val, val1, methodErr := merged.typ3.Lookup(key)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt5 = val
+		retVal.Value1 = val1
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Lookup", Tag: currTag}
+	return
+}
+
+// ArrayMethod multiplexes to different implementations of the method.
+func (merged *Impl) ArrayMethod(sli []*pkg3.Something, arr [32]*pkg3.Something) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.3" {
+		methodErr := merged.typ2.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "ArrayMethod", Tag: currTag}
+	return
+}
+
+// ChanMethod multiplexes to different implementations of the method.
+func (merged *Impl) ChanMethod(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.Something, chan3 chan<- *pkg3.Something) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.3" {
+		methodErr := merged.typ2.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "ChanMethod", Tag: currTag}
+	return
+}
+
+// MapMethod multiplexes to different implementations of the method.
+func (merged *Impl) MapMethod(m map[string]*pkg3.Something) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.3" {
+		methodErr := merged.typ2.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "MapMethod", Tag: currTag}
+	return
+}
+
+// FooBarBaz multiplexes to different implementations of the method.
+func (merged *Impl) FooBarBaz() (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.3" {
+		merged.typ2.FooBarBaz()
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "FooBarBaz", Tag: currTag}
+	return
+}
+
+// Limit multiplexes to different implementations of the method.
+func (merged *Impl) Limit() (retVal string, err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.4" {
+		val, methodErr := merged.typ3.Limit()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Limit", Tag: currTag}
+	return
+}
+
+// Sum multiplexes to different implementations of the method.
+func (merged *Impl) Sum(a int, b int) (retVal int, err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.4" {
+		val, methodErr := merged.typ3.Sum(a, b)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Sum", Tag: currTag}
+	return
+}
+
+// Store multiplexes to different implementations of the method.
+func (merged *Impl) Store(arg0 *big.Int, arg1 string) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.4" {
+		methodErr := merged.typ3.Store(arg0, arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Store", Tag: currTag}
+	return
+}
+
+// Append multiplexes to different implementations of the method.
+func (merged *Impl) Append(prefix string, vals ...*big.Int) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.0.4" {
+		methodErr := merged.typ3.Append(prefix, vals...)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Append", Tag: currTag}
+	return
+}
@@ -0,0 +1,83 @@
+package example_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/forta-network/go-merge-types/example/atomicpkg"
+	"github.com/forta-network/go-merge-types/example/chainpkg"
+	"github.com/forta-network/go-merge-types/example/indexpkg"
+	"github.com/forta-network/go-merge-types/example/outpkg"
+	"github.com/forta-network/go-merge-types/example/pkg3"
+)
+
+type merged interface {
+	Use(tag string) (changed bool)
+	SingleReturnVal(arg string) (int, error)
+}
+
+func benchmarkParallelCalls(b *testing.B, impl merged) {
+	tags := []string{"v0.0.1", "v0.0.2"}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			// switch the tag once in a while
+			if i%1000 == 0 {
+				impl.Use(tags[(i/1000)%len(tags)])
+			}
+			if _, err := impl.SingleReturnVal("arg"); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMutexMode(b *testing.B) {
+	impl, err := outpkg.NewImpl("", 0, 0, &sync.WaitGroup{}, &pkg3.Foo{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkParallelCalls(b, impl)
+}
+
+func BenchmarkAtomicMode(b *testing.B) {
+	impl, err := atomicpkg.NewImpl("", 0, 0, &sync.WaitGroup{}, &pkg3.Foo{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkParallelCalls(b, impl)
+}
+
+type many interface {
+	Use(tag string) (changed bool)
+	NoReturnVal(arg int) error
+}
+
+func benchmarkLastTagCalls(b *testing.B, impl many) {
+	// the last tag is the worst case for the comparison chains
+	impl.Use("v0.1.21")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := impl.NoReturnVal(i); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTagChainDispatch(b *testing.B) {
+	impl, err := chainpkg.NewManyChain("", 0, 0, &sync.WaitGroup{}, &pkg3.Foo{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkLastTagCalls(b, impl)
+}
+
+func BenchmarkIndexDispatch(b *testing.B) {
+	impl, err := indexpkg.NewManyIndex("", 0, 0, &sync.WaitGroup{}, &pkg3.Foo{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkLastTagCalls(b, impl)
+}
@@ -0,0 +1,940 @@
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package chainpkg
+
+import (
+	import_errors "errors"
+	import_fmt "fmt"
+	"math/big"
+	import_sync "sync"
+	import_atomic "sync/atomic"
+
+	pkg1_1 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg1_10 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg1_4 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg1_7 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg2_11 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg2_2 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg2_5 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg2_8 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg3_12 "github.com/forta-network/go-merge-types/example/pkg3"
+	pkg3_3 "github.com/forta-network/go-merge-types/example/pkg3"
+	pkg3_6 "github.com/forta-network/go-merge-types/example/pkg3"
+	pkg3_9 "github.com/forta-network/go-merge-types/example/pkg3"
+)
+
+// ErrNotImplemented matches the errors from the calls to the methods which are not
+// implemented for the current tag.
+var ErrNotImplemented = import_errors.New("not implemented")
+
+// NotImplementedError is returned from a merged type method if the implementation
+// of the current tag does not have the method.
+type NotImplementedError struct {
+	Type   string
+	Method string
+	Tag    string
+}
+
+func (err *NotImplementedError) Error() string {
+	return import_fmt.Sprintf("%s.%s not implemented (tag=%s)", err.Type, err.Method, err.Tag)
+}
+
+// Is makes the error match ErrNotImplemented.
+func (err *NotImplementedError) Is(target error) bool {
+	return target == ErrNotImplemented
+}
+
+// ManyChain is a new type which can multiplex calls to different implementation types.
+type ManyChain struct {
+	typ0    *pkg1_1.Impl1
+	typ1    *pkg2_2.Impl2
+	typ2    *pkg3_3.Impl3
+	typ3    *pkg1_1.Impl1
+	typ4    *pkg2_2.Impl2
+	typ5    *pkg3_3.Impl3
+	typ6    *pkg1_1.Impl1
+	typ7    *pkg2_2.Impl2
+	typ8    *pkg3_3.Impl3
+	typ9    *pkg1_1.Impl1
+	typ10   *pkg2_2.Impl2
+	typ11   *pkg3_3.Impl3
+	currTag import_atomic.Pointer[string]
+}
+
+// NewManyChain creates a new merged type.
+func NewManyChain(arg1 string, arg2 int, arg2Alt1 int64, arg3 *import_sync.WaitGroup, arg4 *pkg3_3.Foo) (*ManyChain, error) {
+	var (
+		mergedType ManyChain
+		err        error
+	)
+	defaultTag := "v0.1.10"
+	mergedType.currTag.Store(&defaultTag)
+
+	mergedType.typ0, err = pkg1_1.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_1.Impl1: %w", err)
+	}
+
+	mergedType.typ1, err = pkg2_2.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_2.Impl2: %w", err)
+	}
+
+	mergedType.typ2, err = pkg3_3.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3_3.Impl3: %w", err)
+	}
+
+	mergedType.typ3, err = pkg1_4.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_4.Impl1: %w", err)
+	}
+
+	mergedType.typ4, err = pkg2_5.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_5.Impl2: %w", err)
+	}
+
+	mergedType.typ5, err = pkg3_6.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3_6.Impl3: %w", err)
+	}
+
+	mergedType.typ6, err = pkg1_7.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_7.Impl1: %w", err)
+	}
+
+	mergedType.typ7, err = pkg2_8.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_8.Impl2: %w", err)
+	}
+
+	mergedType.typ8, err = pkg3_9.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3_9.Impl3: %w", err)
+	}
+
+	mergedType.typ9, err = pkg1_10.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_10.Impl1: %w", err)
+	}
+
+	mergedType.typ10, err = pkg2_11.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_11.Impl2: %w", err)
+	}
+
+	mergedType.typ11, err = pkg3_12.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3_12.Impl3: %w", err)
+	}
+
+	return &mergedType, nil
+}
+
+// ResolveTagForManyChain finds the source tag which is equal to given tag or has a version range containing it.
+func ResolveTagForManyChain(tag string) (string, bool) {
+	if tag == "v0.1.10" {
+		return tag, true
+	}
+
+	if tag == "v0.1.11" {
+		return tag, true
+	}
+
+	if tag == "v0.1.12" {
+		return tag, true
+	}
+
+	if tag == "v0.1.13" {
+		return tag, true
+	}
+
+	if tag == "v0.1.14" {
+		return tag, true
+	}
+
+	if tag == "v0.1.15" {
+		return tag, true
+	}
+
+	if tag == "v0.1.16" {
+		return tag, true
+	}
+
+	if tag == "v0.1.17" {
+		return tag, true
+	}
+
+	if tag == "v0.1.18" {
+		return tag, true
+	}
+
+	if tag == "v0.1.19" {
+		return tag, true
+	}
+
+	if tag == "v0.1.20" {
+		return tag, true
+	}
+
+	if tag == "v0.1.21" {
+		return tag, true
+	}
+
+	return "", false
+}
+
+// IsKnownTagForManyChain tells if given tag is a known tag.
+func IsKnownTagForManyChain(tag string) bool {
+	_, ok := ResolveTagForManyChain(tag)
+	return ok
+}
+
+// Use sets the used implementation to given tag.
+func (merged *ManyChain) Use(tag string) (changed bool) {
+	// use the default tag if the provided tag is unknown
+	tag, ok := ResolveTagForManyChain(tag)
+	if !ok {
+		tag = "v0.1.10"
+	}
+	return *merged.currTag.Swap(&tag) != tag
+}
+
+// Unsafe has no effect since the tag is switched atomically.
+func (merged *ManyChain) Unsafe() {}
+
+// Safe has no effect since the tag is switched atomically.
+func (merged *ManyChain) Safe() {}
+
+// methodsByTagForManyChain are the supported methods of each tag.
+var methodsByTagForManyChain = map[string][]string{
+	"v0.1.10": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.1.11": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.1.12": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+	"v0.1.13": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.1.14": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.1.15": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+	"v0.1.16": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.1.17": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.1.18": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+	"v0.1.19": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.1.20": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.1.21": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+}
+
+// MethodsForTagForManyChain returns the methods which given tag supports. Unknown tags are
+// treated as the default tag.
+func MethodsForTagForManyChain(tag string) []string {
+	tag, ok := ResolveTagForManyChain(tag)
+	if !ok {
+		tag = "v0.1.10"
+	}
+	return append([]string(nil), methodsByTagForManyChain[tag]...)
+}
+
+// Supports tells if the implementation of the current tag supports given method.
+func (merged *ManyChain) Supports(method string) bool {
+	return merged.SupportsForTag(*merged.currTag.Load(), method)
+}
+
+// SupportsForTag tells if the implementation of given tag supports given method.
+func (merged *ManyChain) SupportsForTag(tag, method string) bool {
+	for _, supported := range MethodsForTagForManyChain(tag) {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
+// FooOutput is a merged return type.
+type FooOutput struct {
+	A         string
+	B         float32
+	Value     *pkg2_2.Int
+	ValueAlt3 *big.Int
+}
+
+// Foo multiplexes to different implementations of the method.
+func (merged *ManyChain) Foo(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (retVal *FooOutput, err error) {
+	currTag := *merged.currTag.Load()
+
+	retVal = &FooOutput{}
+
+	if currTag == "v0.1.10" {
+		val, methodErr := merged.typ0.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+	}
+
+	if currTag == "v0.1.11" {
+		val, methodErr := merged.typ1.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+	}
+
+	if currTag == "v0.1.12" {
+		val, methodErr := merged.typ2.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+	}
+
+	if currTag == "v0.1.13" {
+		val, methodErr := merged.typ3.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+	}
+
+	if currTag == "v0.1.14" {
+		val, methodErr := merged.typ4.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+	}
+
+	if currTag == "v0.1.15" {
+		val, methodErr := merged.typ5.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+	}
+
+	if currTag == "v0.1.16" {
+		val, methodErr := merged.typ6.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+	}
+
+	if currTag == "v0.1.17" {
+		val, methodErr := merged.typ7.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+	}
+
+	if currTag == "v0.1.18" {
+		val, methodErr := merged.typ8.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+	}
+
+	if currTag == "v0.1.19" {
+		val, methodErr := merged.typ9.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+	}
+
+	if currTag == "v0.1.20" {
+		val, methodErr := merged.typ10.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+	}
+
+	if currTag == "v0.1.21" {
+		val, methodErr := merged.typ11.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "Foo", Tag: currTag}
+	return
+}
+
+// Bar multiplexes to different implementations of the method.
+func (merged *ManyChain) Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.1.10" {
+		merged.typ0.Bar(arg1)
+
+		return
+	}
+
+	if currTag == "v0.1.12" {
+		methodErr := merged.typ2.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.13" {
+		merged.typ3.Bar(arg1)
+
+		return
+	}
+
+	if currTag == "v0.1.15" {
+		methodErr := merged.typ5.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.16" {
+		merged.typ6.Bar(arg1)
+
+		return
+	}
+
+	if currTag == "v0.1.18" {
+		methodErr := merged.typ8.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.19" {
+		merged.typ9.Bar(arg1)
+
+		return
+	}
+
+	if currTag == "v0.1.21" {
+		methodErr := merged.typ11.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "Bar", Tag: currTag}
+	return
+}
+
+// SingleReturnVal multiplexes to different implementations of the method.
+func (merged *ManyChain) SingleReturnVal(arg string) (retVal int, err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.1.10" {
+		val, methodErr := merged.typ0.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if currTag == "v0.1.11" {
+		val, methodErr := merged.typ1.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if currTag == "v0.1.13" {
+		val, methodErr := merged.typ3.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if currTag == "v0.1.14" {
+		val, methodErr := merged.typ4.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if currTag == "v0.1.16" {
+		val, methodErr := merged.typ6.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if currTag == "v0.1.17" {
+		val, methodErr := merged.typ7.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if currTag == "v0.1.19" {
+		val, methodErr := merged.typ9.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if currTag == "v0.1.20" {
+		val, methodErr := merged.typ10.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "SingleReturnVal", Tag: currTag}
+	return
+}
+
+// NoReturnVal multiplexes to different implementations of the method.
+func (merged *ManyChain) NoReturnVal(arg int) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.1.11" {
+		methodErr := merged.typ1.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.12" {
+		methodErr := merged.typ2.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.14" {
+		methodErr := merged.typ4.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.15" {
+		methodErr := merged.typ5.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.17" {
+		methodErr := merged.typ7.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.18" {
+		methodErr := merged.typ8.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.20" {
+		methodErr := merged.typ10.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.21" {
+		methodErr := merged.typ11.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "NoReturnVal", Tag: currTag}
+	return
+}
+
+// Lookup multiplexes to different implementations of the method.
+func (merged *ManyChain) Lookup(key string) (retVal int, retVal1 bool, err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.1.11" {
+		val, val1 := merged.typ1.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	if currTag == "v0.1.14" {
+		val, val1 := merged.typ4.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	if currTag == "v0.1.17" {
+		val, val1 := merged.typ7.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	if currTag == "v0.1.20" {
+		val, val1 := merged.typ10.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "Lookup", Tag: currTag}
+	return
+}
+
+// ArrayMethod multiplexes to different implementations of the method.
+func (merged *ManyChain) ArrayMethod(sli []*pkg3_3.Something, arr [32]*pkg3_3.Something) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.1.12" {
+		methodErr := merged.typ2.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.15" {
+		methodErr := merged.typ5.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.18" {
+		methodErr := merged.typ8.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.21" {
+		methodErr := merged.typ11.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "ArrayMethod", Tag: currTag}
+	return
+}
+
+// ChanMethod multiplexes to different implementations of the method.
+func (merged *ManyChain) ChanMethod(chan1 chan *pkg3_3.Something, chan2 <-chan *pkg3_3.Something, chan3 chan<- *pkg3_3.Something) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.1.12" {
+		methodErr := merged.typ2.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.15" {
+		methodErr := merged.typ5.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.18" {
+		methodErr := merged.typ8.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.21" {
+		methodErr := merged.typ11.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "ChanMethod", Tag: currTag}
+	return
+}
+
+// MapMethod multiplexes to different implementations of the method.
+func (merged *ManyChain) MapMethod(m map[string]*pkg3_3.Something) (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.1.12" {
+		methodErr := merged.typ2.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.15" {
+		methodErr := merged.typ5.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.18" {
+		methodErr := merged.typ8.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if currTag == "v0.1.21" {
+		methodErr := merged.typ11.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "MapMethod", Tag: currTag}
+	return
+}
+
+// FooBarBaz multiplexes to different implementations of the method.
+func (merged *ManyChain) FooBarBaz() (err error) {
+	currTag := *merged.currTag.Load()
+
+	if currTag == "v0.1.12" {
+		merged.typ2.FooBarBaz()
+
+		return
+	}
+
+	if currTag == "v0.1.15" {
+		merged.typ5.FooBarBaz()
+
+		return
+	}
+
+	if currTag == "v0.1.18" {
+		merged.typ8.FooBarBaz()
+
+		return
+	}
+
+	if currTag == "v0.1.21" {
+		merged.typ11.FooBarBaz()
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyChain", Method: "FooBarBaz", Tag: currTag}
+	return
+}
@@ -0,0 +1,51 @@
+package example_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/forta-network/go-merge-types/example/outpkg"
+	"github.com/forta-network/go-merge-types/example/pkg3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotImplementedError(t *testing.T) {
+	r := require.New(t)
+
+	impl, err := outpkg.NewImpl("", 0, 0, &sync.WaitGroup{}, &pkg3.Foo{})
+	r.NoError(err)
+
+	impl.Use("v0.0.1")
+	err = fmt.Errorf("failed: %w", impl.ChanMethod(nil, nil, nil))
+	r.True(errors.Is(err, outpkg.ErrNotImplemented))
+	r.Equal("failed: Impl.ChanMethod not implemented (tag=v0.0.1)", err.Error())
+
+	var notImplErr *outpkg.NotImplementedError
+	r.True(errors.As(err, &notImplErr))
+	r.Equal("ChanMethod", notImplErr.Method)
+	r.Equal("v0.0.1", notImplErr.Tag)
+
+	r.False(errors.Is(errors.New("not implemented"), outpkg.ErrNotImplemented))
+}
+
+func TestResolveTag(t *testing.T) {
+	r := require.New(t)
+
+	for tag, expected := range map[string]string{
+		"v0.0.1":      "v0.0.1",
+		"v0.0.3":      "v0.0.3",
+		"0.0.7":       "v0.0.3",
+		"v0.0.9+meta": "v0.0.3",
+		"v0.1.0-rc.1": "v0.0.3",
+	} {
+		resolved, ok := outpkg.ResolveTagForImpl(tag)
+		r.True(ok, tag)
+		r.Equal(expected, resolved, tag)
+	}
+	for _, tag := range []string{"v0.1.0", "v0.0.3-rc.1", "v0.0", "unknown"} {
+		_, ok := outpkg.ResolveTagForImpl(tag)
+		r.False(ok, tag)
+	}
+}
@@ -0,0 +1,921 @@
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package indexpkg
+
+import (
+	import_errors "errors"
+	import_fmt "fmt"
+	"math/big"
+	import_sync "sync"
+	import_atomic "sync/atomic"
+
+	pkg1_1 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg1_10 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg1_4 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg1_7 "github.com/forta-network/go-merge-types/example/pkg1"
+	pkg2_11 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg2_2 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg2_5 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg2_8 "github.com/forta-network/go-merge-types/example/pkg2"
+	pkg3_12 "github.com/forta-network/go-merge-types/example/pkg3"
+	pkg3_3 "github.com/forta-network/go-merge-types/example/pkg3"
+	pkg3_6 "github.com/forta-network/go-merge-types/example/pkg3"
+	pkg3_9 "github.com/forta-network/go-merge-types/example/pkg3"
+)
+
+// ErrNotImplemented matches the errors from the calls to the methods which are not
+// implemented for the current tag.
+var ErrNotImplemented = import_errors.New("not implemented")
+
+// NotImplementedError is returned from a merged type method if the implementation
+// of the current tag does not have the method.
+type NotImplementedError struct {
+	Type   string
+	Method string
+	Tag    string
+}
+
+func (err *NotImplementedError) Error() string {
+	return import_fmt.Sprintf("%s.%s not implemented (tag=%s)", err.Type, err.Method, err.Tag)
+}
+
+// Is makes the error match ErrNotImplemented.
+func (err *NotImplementedError) Is(target error) bool {
+	return target == ErrNotImplemented
+}
+
+// ManyIndex is a new type which can multiplex calls to different implementation types.
+type ManyIndex struct {
+	typ0      *pkg1_1.Impl1
+	typ1      *pkg2_2.Impl2
+	typ2      *pkg3_3.Impl3
+	typ3      *pkg1_1.Impl1
+	typ4      *pkg2_2.Impl2
+	typ5      *pkg3_3.Impl3
+	typ6      *pkg1_1.Impl1
+	typ7      *pkg2_2.Impl2
+	typ8      *pkg3_3.Impl3
+	typ9      *pkg1_1.Impl1
+	typ10     *pkg2_2.Impl2
+	typ11     *pkg3_3.Impl3
+	currIndex import_atomic.Int32
+}
+
+// NewManyIndex creates a new merged type.
+func NewManyIndex(arg1 string, arg2 int, arg2Alt1 int64, arg3 *import_sync.WaitGroup, arg4 *pkg3_3.Foo) (*ManyIndex, error) {
+	var (
+		mergedType ManyIndex
+		err        error
+	)
+	mergedType.currIndex.Store(tagIndexesForManyIndex["v0.1.10"])
+
+	mergedType.typ0, err = pkg1_1.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_1.Impl1: %w", err)
+	}
+
+	mergedType.typ1, err = pkg2_2.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_2.Impl2: %w", err)
+	}
+
+	mergedType.typ2, err = pkg3_3.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3_3.Impl3: %w", err)
+	}
+
+	mergedType.typ3, err = pkg1_4.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_4.Impl1: %w", err)
+	}
+
+	mergedType.typ4, err = pkg2_5.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_5.Impl2: %w", err)
+	}
+
+	mergedType.typ5, err = pkg3_6.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3_6.Impl3: %w", err)
+	}
+
+	mergedType.typ6, err = pkg1_7.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_7.Impl1: %w", err)
+	}
+
+	mergedType.typ7, err = pkg2_8.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_8.Impl2: %w", err)
+	}
+
+	mergedType.typ8, err = pkg3_9.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3_9.Impl3: %w", err)
+	}
+
+	mergedType.typ9, err = pkg1_10.NewImpl1(arg1, arg2)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg1_10.Impl1: %w", err)
+	}
+
+	mergedType.typ10, err = pkg2_11.NewImpl2(arg2Alt1)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg2_11.Impl2: %w", err)
+	}
+
+	mergedType.typ11, err = pkg3_12.NewImpl3(arg2, arg3, arg4)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize pkg3_12.Impl3: %w", err)
+	}
+
+	return &mergedType, nil
+}
+
+// tagsForManyIndex are the source tags by the dispatch indexes.
+var tagsForManyIndex = []string{"v0.1.10", "v0.1.11", "v0.1.12", "v0.1.13", "v0.1.14", "v0.1.15", "v0.1.16", "v0.1.17", "v0.1.18", "v0.1.19", "v0.1.20", "v0.1.21"}
+
+// tagIndexesForManyIndex are the dispatch indexes of the source tags.
+var tagIndexesForManyIndex = map[string]int32{
+	"v0.1.10": 0,
+	"v0.1.11": 1,
+	"v0.1.12": 2,
+	"v0.1.13": 3,
+	"v0.1.14": 4,
+	"v0.1.15": 5,
+	"v0.1.16": 6,
+	"v0.1.17": 7,
+	"v0.1.18": 8,
+	"v0.1.19": 9,
+	"v0.1.20": 10,
+	"v0.1.21": 11,
+}
+
+// ResolveTagForManyIndex finds the source tag which is equal to given tag or has a version range containing it.
+func ResolveTagForManyIndex(tag string) (string, bool) {
+	if tag == "v0.1.10" {
+		return tag, true
+	}
+
+	if tag == "v0.1.11" {
+		return tag, true
+	}
+
+	if tag == "v0.1.12" {
+		return tag, true
+	}
+
+	if tag == "v0.1.13" {
+		return tag, true
+	}
+
+	if tag == "v0.1.14" {
+		return tag, true
+	}
+
+	if tag == "v0.1.15" {
+		return tag, true
+	}
+
+	if tag == "v0.1.16" {
+		return tag, true
+	}
+
+	if tag == "v0.1.17" {
+		return tag, true
+	}
+
+	if tag == "v0.1.18" {
+		return tag, true
+	}
+
+	if tag == "v0.1.19" {
+		return tag, true
+	}
+
+	if tag == "v0.1.20" {
+		return tag, true
+	}
+
+	if tag == "v0.1.21" {
+		return tag, true
+	}
+
+	return "", false
+}
+
+// IsKnownTagForManyIndex tells if given tag is a known tag.
+func IsKnownTagForManyIndex(tag string) bool {
+	_, ok := ResolveTagForManyIndex(tag)
+	return ok
+}
+
+// Use sets the used implementation to given tag.
+func (merged *ManyIndex) Use(tag string) (changed bool) {
+	// use the default tag if the provided tag is unknown
+	tag, ok := ResolveTagForManyIndex(tag)
+	if !ok {
+		tag = "v0.1.10"
+	}
+	index := tagIndexesForManyIndex[tag]
+	return merged.currIndex.Swap(index) != index
+}
+
+// Unsafe has no effect since the tag is switched atomically.
+func (merged *ManyIndex) Unsafe() {}
+
+// Safe has no effect since the tag is switched atomically.
+func (merged *ManyIndex) Safe() {}
+
+// methodsByTagForManyIndex are the supported methods of each tag.
+var methodsByTagForManyIndex = map[string][]string{
+	"v0.1.10": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.1.11": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.1.12": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+	"v0.1.13": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.1.14": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.1.15": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+	"v0.1.16": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.1.17": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.1.18": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+	"v0.1.19": {"Foo", "Bar", "SingleReturnVal"},
+	"v0.1.20": {"Foo", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.1.21": {"Foo", "Bar", "NoReturnVal", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+}
+
+// MethodsForTagForManyIndex returns the methods which given tag supports. Unknown tags are
+// treated as the default tag.
+func MethodsForTagForManyIndex(tag string) []string {
+	tag, ok := ResolveTagForManyIndex(tag)
+	if !ok {
+		tag = "v0.1.10"
+	}
+	return append([]string(nil), methodsByTagForManyIndex[tag]...)
+}
+
+// Supports tells if the implementation of the current tag supports given method.
+func (merged *ManyIndex) Supports(method string) bool {
+	return merged.SupportsForTag(tagsForManyIndex[merged.currIndex.Load()], method)
+}
+
+// SupportsForTag tells if the implementation of given tag supports given method.
+func (merged *ManyIndex) SupportsForTag(tag, method string) bool {
+	for _, supported := range MethodsForTagForManyIndex(tag) {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
+// FooOutput is a merged return type.
+type FooOutput struct {
+	A         string
+	B         float32
+	Value     *pkg2_2.Int
+	ValueAlt3 *big.Int
+}
+
+// Foo multiplexes to different implementations of the method.
+func (merged *ManyIndex) Foo(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (retVal *FooOutput, err error) {
+	currIndex := merged.currIndex.Load()
+
+	retVal = &FooOutput{}
+
+	switch currIndex {
+	case 0:
+		val, methodErr := merged.typ0.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+
+	case 1:
+		val, methodErr := merged.typ1.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+
+	case 2:
+		val, methodErr := merged.typ2.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+
+	case 3:
+		val, methodErr := merged.typ3.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+
+	case 4:
+		val, methodErr := merged.typ4.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+
+	case 5:
+		val, methodErr := merged.typ5.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+
+	case 6:
+		val, methodErr := merged.typ6.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+
+	case 7:
+		val, methodErr := merged.typ7.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+
+	case 8:
+		val, methodErr := merged.typ8.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+
+	case 9:
+		val, methodErr := merged.typ9.Foo(arg1)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+
+	case 10:
+		val, methodErr := merged.typ10.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+
+	case 11:
+		val, methodErr := merged.typ11.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "Foo", Tag: tagsForManyIndex[currIndex]}
+	return
+}
+
+// Bar multiplexes to different implementations of the method.
+func (merged *ManyIndex) Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error) {
+	currIndex := merged.currIndex.Load()
+
+	switch currIndex {
+	case 0:
+		merged.typ0.Bar(arg1)
+
+		return
+
+	case 2:
+		methodErr := merged.typ2.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 3:
+		merged.typ3.Bar(arg1)
+
+		return
+
+	case 5:
+		methodErr := merged.typ5.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 6:
+		merged.typ6.Bar(arg1)
+
+		return
+
+	case 8:
+		methodErr := merged.typ8.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 9:
+		merged.typ9.Bar(arg1)
+
+		return
+
+	case 11:
+		methodErr := merged.typ11.Bar(arg1Alt4)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "Bar", Tag: tagsForManyIndex[currIndex]}
+	return
+}
+
+// SingleReturnVal multiplexes to different implementations of the method.
+func (merged *ManyIndex) SingleReturnVal(arg string) (retVal int, err error) {
+	currIndex := merged.currIndex.Load()
+
+	switch currIndex {
+	case 0:
+		val, methodErr := merged.typ0.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+
+	case 1:
+		val, methodErr := merged.typ1.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+
+	case 3:
+		val, methodErr := merged.typ3.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+
+	case 4:
+		val, methodErr := merged.typ4.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+
+	case 6:
+		val, methodErr := merged.typ6.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+
+	case 7:
+		val, methodErr := merged.typ7.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+
+	case 9:
+		val, methodErr := merged.typ9.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+
+	case 10:
+		val, methodErr := merged.typ10.SingleReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "SingleReturnVal", Tag: tagsForManyIndex[currIndex]}
+	return
+}
+
+// NoReturnVal multiplexes to different implementations of the method.
+func (merged *ManyIndex) NoReturnVal(arg int) (err error) {
+	currIndex := merged.currIndex.Load()
+
+	switch currIndex {
+	case 1:
+		methodErr := merged.typ1.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 2:
+		methodErr := merged.typ2.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 4:
+		methodErr := merged.typ4.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 5:
+		methodErr := merged.typ5.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 7:
+		methodErr := merged.typ7.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 8:
+		methodErr := merged.typ8.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 10:
+		methodErr := merged.typ10.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 11:
+		methodErr := merged.typ11.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "NoReturnVal", Tag: tagsForManyIndex[currIndex]}
+	return
+}
+
+// Lookup multiplexes to different implementations of the method.
+func (merged *ManyIndex) Lookup(key string) (retVal int, retVal1 bool, err error) {
+	currIndex := merged.currIndex.Load()
+
+	switch currIndex {
+	case 1:
+		val, val1 := merged.typ1.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+
+	case 4:
+		val, val1 := merged.typ4.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+
+	case 7:
+		val, val1 := merged.typ7.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+
+	case 10:
+		val, val1 := merged.typ10.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "Lookup", Tag: tagsForManyIndex[currIndex]}
+	return
+}
+
+// ArrayMethod multiplexes to different implementations of the method.
+func (merged *ManyIndex) ArrayMethod(sli []*pkg3_3.Something, arr [32]*pkg3_3.Something) (err error) {
+	currIndex := merged.currIndex.Load()
+
+	switch currIndex {
+	case 2:
+		methodErr := merged.typ2.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 5:
+		methodErr := merged.typ5.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 8:
+		methodErr := merged.typ8.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 11:
+		methodErr := merged.typ11.ArrayMethod(sli, arr)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "ArrayMethod", Tag: tagsForManyIndex[currIndex]}
+	return
+}
+
+// ChanMethod multiplexes to different implementations of the method.
+func (merged *ManyIndex) ChanMethod(chan1 chan *pkg3_3.Something, chan2 <-chan *pkg3_3.Something, chan3 chan<- *pkg3_3.Something) (err error) {
+	currIndex := merged.currIndex.Load()
+
+	switch currIndex {
+	case 2:
+		methodErr := merged.typ2.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 5:
+		methodErr := merged.typ5.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 8:
+		methodErr := merged.typ8.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 11:
+		methodErr := merged.typ11.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "ChanMethod", Tag: tagsForManyIndex[currIndex]}
+	return
+}
+
+// MapMethod multiplexes to different implementations of the method.
+func (merged *ManyIndex) MapMethod(m map[string]*pkg3_3.Something) (err error) {
+	currIndex := merged.currIndex.Load()
+
+	switch currIndex {
+	case 2:
+		methodErr := merged.typ2.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 5:
+		methodErr := merged.typ5.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 8:
+		methodErr := merged.typ8.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+
+	case 11:
+		methodErr := merged.typ11.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "MapMethod", Tag: tagsForManyIndex[currIndex]}
+	return
+}
+
+// FooBarBaz multiplexes to different implementations of the method.
+func (merged *ManyIndex) FooBarBaz() (err error) {
+	currIndex := merged.currIndex.Load()
+
+	switch currIndex {
+	case 2:
+		merged.typ2.FooBarBaz()
+
+		return
+
+	case 5:
+		merged.typ5.FooBarBaz()
+
+		return
+
+	case 8:
+		merged.typ8.FooBarBaz()
+
+		return
+
+	case 11:
+		merged.typ11.FooBarBaz()
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "ManyIndex", Method: "FooBarBaz", Tag: tagsForManyIndex[currIndex]}
+	return
+}
@@ -0,0 +1,58 @@
+package example_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/forta-network/go-merge-types/example/outpkg"
+	"github.com/forta-network/go-merge-types/example/pkg3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastError(t *testing.T) {
+	r := require.New(t)
+
+	impl, err := outpkg.NewImpl("", 0, 0, &sync.WaitGroup{}, &pkg3.Foo{})
+	r.NoError(err)
+
+	// not implemented: zero values
+	impl.Use("v0.0.1")
+	val, ok := impl.Lookup("key")
+	r.Zero(val)
+	r.False(ok)
+	r.ErrorIs(impl.LastError(), outpkg.ErrNotImplemented)
+
+	// the error is cleared by the next call
+	impl.Use("v0.0.2")
+	impl.Lookup("key")
+	r.NoError(impl.LastError())
+}
+
+func TestViewLastError(t *testing.T) {
+	r := require.New(t)
+
+	impl, err := outpkg.NewImpl("", 0, 0, &sync.WaitGroup{}, &pkg3.Foo{})
+	r.NoError(err)
+
+	// each view keeps its own error while the views are used concurrently
+	views := []*outpkg.ImplView{impl.WithTag("v0.0.1"), impl.WithTag("v0.0.2")}
+	errs := make([][]error, len(views))
+	var wg sync.WaitGroup
+	for i, view := range views {
+		wg.Go(func() {
+			for range 100 {
+				view.Lookup("key")
+				errs[i] = append(errs[i], view.LastError())
+			}
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs[0] {
+		r.ErrorIs(err, outpkg.ErrNotImplemented)
+	}
+	for _, err := range errs[1] {
+		r.NoError(err)
+	}
+	r.NoError(impl.LastError())
+}
@@ -3,84 +3,194 @@
 package outpkg
 
 import (
+	import_errors "errors"
 	import_fmt "fmt"
+	"math/big"
+	import_strconv "strconv"
+	import_strings "strings"
 	import_sync "sync"
-
+	import_atomic "sync/atomic"
 
 	pkg1 "github.com/forta-network/go-merge-types/example/pkg1"
-
 	pkg2_2 "github.com/forta-network/go-merge-types/example/pkg2"
-
 	pkg3 "github.com/forta-network/go-merge-types/example/pkg3"
+)
 
+// ErrNotImplemented matches the errors from the calls to the methods which are not
+// implemented for the current tag.
+var ErrNotImplemented = import_errors.New("not implemented")
 
+// NotImplementedError is returned from a merged type method if the implementation
+// of the current tag does not have the method.
+type NotImplementedError struct {
+	Type   string
+	Method string
+	Tag    string
+}
 
-	biggie "math/big"
-
-	"math/big"
-
-	"sync"
+func (err *NotImplementedError) Error() string {
+	return import_fmt.Sprintf("%s.%s not implemented (tag=%s)", err.Type, err.Method, err.Tag)
+}
 
-)
+// Is makes the error match ErrNotImplemented.
+func (err *NotImplementedError) Is(target error) bool {
+	return target == ErrNotImplemented
+}
 
 // Impl is a new type which can multiplex calls to different implementation types.
 type Impl struct {
-
-	typ0 *pkg1.Impl1
-
-	typ1 *pkg2_2.Impl2
-
-	typ2 *pkg3.Impl3
-
+	typ0    *pkg1.Impl1
+	typ1    *pkg2_2.Impl2
+	typ2    *pkg3.Impl3
 	currTag string
-	mu import_sync.RWMutex
-	unsafe bool // default: false
+	mu      import_sync.RWMutex
+	unsafe  bool // default: false
+	lastErr import_atomic.Pointer[error]
 }
 
 // NewImpl creates a new merged type.
-func NewImpl(arg1 string, arg2 int, arg2Alt1 int64, arg3 *sync.WaitGroup, arg4 *pkg3.Foo) (*Impl, error) {
+func NewImpl(arg1 string, arg2 int, arg2Alt1 int64, arg3 *import_sync.WaitGroup, arg4 *pkg3.Foo) (*Impl, error) {
 	var (
 		mergedType Impl
-		err error
+		err        error
 	)
 	mergedType.currTag = "v0.0.3"
 
-
 	mergedType.typ0, err = pkg1.NewImpl1(arg1, arg2)
 	if err != nil {
-		return nil, import_fmt.Errorf("failed to initialize pkg1.Impl1: %v", err)
+		return nil, import_fmt.Errorf("failed to initialize pkg1.Impl1: %w", err)
 	}
 
 	mergedType.typ1, err = pkg2_2.NewImpl2(arg2Alt1)
 	if err != nil {
-		return nil, import_fmt.Errorf("failed to initialize pkg2_2.Impl2: %v", err)
+		return nil, import_fmt.Errorf("failed to initialize pkg2_2.Impl2: %w", err)
 	}
 
 	mergedType.typ2, err = pkg3.NewImpl3(arg2, arg3, arg4)
 	if err != nil {
-		return nil, import_fmt.Errorf("failed to initialize pkg3.Impl3: %v", err)
+		return nil, import_fmt.Errorf("failed to initialize pkg3.Impl3: %w", err)
 	}
 
-
 	return &mergedType, nil
 }
 
-// IsKnownTagForImpl tells if given tag is a known tag.
-func IsKnownTagForImpl(tag string) bool {
-
+// ResolveTagForImpl finds the source tag which is equal to given tag or has a version range containing it.
+func ResolveTagForImpl(tag string) (string, bool) {
 	if tag == "v0.0.1" {
-		return true
+		return tag, true
 	}
 
 	if tag == "v0.0.2" {
-		return true
+		return tag, true
 	}
 
 	if tag == "v0.0.3" {
-		return true
+		return tag, true
 	}
 
-	return false
+	version, ok := parseVersionForImpl(tag)
+	if !ok {
+		return "", false
+	}
+
+	if compareVersionsForImpl(version, versionForImpl{0, 0, 3, ""}) >= 0 && compareVersionsForImpl(version, versionForImpl{0, 1, 0, ""}) < 0 {
+		return "v0.0.3", true
+	}
+
+	return "", false
+}
+
+// versionForImpl is a semantic version of a tag.
+type versionForImpl struct {
+	major, minor, patch uint64
+	preRelease          string
+}
+
+// parseVersionForImpl parses a semantic version. The "v" prefix and the build metadata are optional.
+func parseVersionForImpl(s string) (version versionForImpl, ok bool) {
+	input := import_strings.TrimPrefix(import_strings.TrimSpace(s), "v")
+	if i := import_strings.Index(input, "+"); i >= 0 {
+		input = input[:i]
+	}
+	if i := import_strings.Index(input, "-"); i >= 0 {
+		version.preRelease = input[i+1:]
+		input = input[:i]
+		if len(version.preRelease) == 0 {
+			return version, false
+		}
+	}
+	parts := import_strings.Split(input, ".")
+	if len(parts) != 3 {
+		return version, false
+	}
+	nums := []*uint64{&version.major, &version.minor, &version.patch}
+	for i, part := range parts {
+		num, err := import_strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return version, false
+		}
+		*nums[i] = num
+	}
+	return version, true
+}
+
+// compareVersionsForImpl returns -1, 0 or 1 if a is less than, equal to or greater than b.
+func compareVersionsForImpl(a, b versionForImpl) int {
+	if c := compareUintsForImpl(a.major, b.major); c != 0 {
+		return c
+	}
+	if c := compareUintsForImpl(a.minor, b.minor); c != 0 {
+		return c
+	}
+	if c := compareUintsForImpl(a.patch, b.patch); c != 0 {
+		return c
+	}
+	// a version without pre-release identifiers has the higher precedence
+	switch {
+	case a.preRelease == b.preRelease:
+		return 0
+	case len(a.preRelease) == 0:
+		return 1
+	case len(b.preRelease) == 0:
+		return -1
+	}
+	aParts, bParts := import_strings.Split(a.preRelease, "."), import_strings.Split(b.preRelease, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := import_strconv.ParseUint(aParts[i], 10, 64)
+		bNum, bErr := import_strconv.ParseUint(bParts[i], 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareUintsForImpl(aNum, bNum)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = import_strings.Compare(aParts[i], bParts[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareUintsForImpl(uint64(len(aParts)), uint64(len(bParts)))
+}
+
+// compareUintsForImpl returns -1, 0 or 1 if a is less than, equal to or greater than b.
+func compareUintsForImpl(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// IsKnownTagForImpl tells if given tag is a known tag.
+func IsKnownTagForImpl(tag string) bool {
+	_, ok := ResolveTagForImpl(tag)
+	return ok
 }
 
 // Use sets the used implementation to given tag.
@@ -90,7 +200,8 @@ func (merged *Impl) Use(tag string) (changed bool) {
 		defer merged.mu.Unlock()
 	}
 	// use the default tag if the provided tag is unknown
-	if !IsKnownTagForImpl(tag) {
+	tag, ok := ResolveTagForImpl(tag)
+	if !ok {
 		tag = "v0.0.3"
 	}
 	changed = merged.currTag != tag
@@ -108,102 +219,742 @@ func (merged *Impl) Safe() {
 	merged.unsafe = false
 }
 
+// LastError returns the error of the last call to a method which does not return an error.
+func (merged *Impl) LastError() error {
+	if err := merged.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
 
+func (merged *Impl) setLastError(err error) {
+	merged.lastErr.Store(&err)
+}
 
-// FooOutput is a merged return type.
-type FooOutput struct {
+// methodsByTagForImpl are the supported methods of each tag.
+var methodsByTagForImpl = map[string][]string{
+	"v0.0.1": {"Foo", "Bar", "SingleReturnVal", "NoReturnVal"},
+	"v0.0.2": {"Foo", "Bar", "SingleReturnVal", "NoReturnVal", "Lookup"},
+	"v0.0.3": {"Foo", "Bar", "SingleReturnVal", "NoReturnVal", "Lookup", "ArrayMethod", "ChanMethod", "MapMethod", "FooBarBaz"},
+}
 
-	A string
+// MethodsForTagForImpl returns the methods which given tag supports. Unknown tags are
+// treated as the default tag.
+func MethodsForTagForImpl(tag string) []string {
+	tag, ok := ResolveTagForImpl(tag)
+	if !ok {
+		tag = "v0.0.3"
+	}
+	return append([]string(nil), methodsByTagForImpl[tag]...)
+}
 
-	B float32
+// Supports tells if the implementation of the current tag supports given method.
+func (merged *Impl) Supports(method string) bool {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+	return merged.SupportsForTag(merged.currTag, method)
+}
 
-	Value *pkg2_2.Int
+// SupportsForTag tells if the implementation of given tag supports given method.
+func (merged *Impl) SupportsForTag(tag, method string) bool {
+	for _, supported := range MethodsForTagForImpl(tag) {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
 
-	ValueAlt3 *big.Int
+// ImplInterface is an interface for Impl.
+type ImplInterface interface {
+	Use(tag string) (changed bool)
+	LastError() error
+
+	Foo(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (retVal *FooOutput, err error)
+	Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error)
+	SingleReturnVal(arg string) (retVal int, err error)
+	NoReturnVal(arg int) (err error)
+	Lookup(key string) (retVal int, retVal1 bool)
+	ArrayMethod(sli []*pkg3.Something, arr [32]*pkg3.Something) (err error)
+	ChanMethod(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.Something, chan3 chan<- *pkg3.Something) (err error)
+	MapMethod(m map[string]*pkg3.Something) (err error)
+	FooBarBaz()
+}
+
+var _ ImplInterface = &Impl{}
+
+// FakeImpl is a fake Impl which records the calls and returns the programmed values.
+type FakeImpl struct {
+	mu import_sync.Mutex
+
+	UseCalls []string
+	UseFunc  func(tag string) (changed bool)
+	LastErr  error
 
+	FooCalls []FakeImplFooCall
+	FooFunc  func(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (retVal *FooOutput, err error)
+
+	BarCalls []FakeImplBarCall
+	BarFunc  func(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error)
+
+	SingleReturnValCalls []FakeImplSingleReturnValCall
+	SingleReturnValFunc  func(arg string) (retVal int, err error)
+
+	NoReturnValCalls []FakeImplNoReturnValCall
+	NoReturnValFunc  func(arg int) (err error)
+
+	LookupCalls []FakeImplLookupCall
+	LookupFunc  func(key string) (retVal int, retVal1 bool)
+
+	ArrayMethodCalls []FakeImplArrayMethodCall
+	ArrayMethodFunc  func(sli []*pkg3.Something, arr [32]*pkg3.Something) (err error)
+
+	ChanMethodCalls []FakeImplChanMethodCall
+	ChanMethodFunc  func(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.Something, chan3 chan<- *pkg3.Something) (err error)
+
+	MapMethodCalls []FakeImplMapMethodCall
+	MapMethodFunc  func(m map[string]*pkg3.Something) (err error)
+
+	FooBarBazCalls []FakeImplFooBarBazCall
+	FooBarBazFunc  func()
 }
 
-// Foo multiplexes to different implementations of the method.
-func (merged *Impl) Foo(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *biggie.Int) (retVal *FooOutput, err error) {
-	if !merged.unsafe {
-		merged.mu.RLock()
-		defer merged.mu.RUnlock()
+var _ ImplInterface = &FakeImpl{}
+
+// Use records the call and returns the programmed value.
+func (fake *FakeImpl) Use(tag string) (changed bool) {
+	fake.mu.Lock()
+	fake.UseCalls = append(fake.UseCalls, tag)
+	fn := fake.UseFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(tag)
+	}
+	return
+}
+
+// LastError returns the programmed error.
+func (fake *FakeImpl) LastError() error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return fake.LastErr
+}
+
+// FakeImplFooCall is a recorded call to FakeImpl.Foo.
+type FakeImplFooCall struct {
+	Arg1     string
+	Arg2     int
+	Arg3     map[string]interface{}
+	Arg3Alt2 *big.Int
+}
+
+// Foo records the call and returns the programmed values.
+func (fake *FakeImpl) Foo(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (retVal *FooOutput, err error) {
+	fake.mu.Lock()
+	fake.FooCalls = append(fake.FooCalls, FakeImplFooCall{
+		Arg1:     arg1,
+		Arg2:     arg2,
+		Arg3:     arg3,
+		Arg3Alt2: arg3Alt2,
+	})
+	fn := fake.FooFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(arg1, arg2, arg3, arg3Alt2)
+	}
+	return
+}
+
+// FooReturns programs the values to return from Foo.
+func (fake *FakeImpl) FooReturns(retVal *FooOutput, err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.FooFunc = func(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (*FooOutput, error) {
+		return retVal, err
+	}
+}
+
+// FakeImplBarCall is a recorded call to FakeImpl.Bar.
+type FakeImplBarCall struct {
+	Arg1     chan *string
+	Arg1Alt4 map[string]interface{}
+}
+
+// Bar records the call and returns the programmed values.
+func (fake *FakeImpl) Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error) {
+	fake.mu.Lock()
+	fake.BarCalls = append(fake.BarCalls, FakeImplBarCall{
+		Arg1:     arg1,
+		Arg1Alt4: arg1Alt4,
+	})
+	fn := fake.BarFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(arg1, arg1Alt4)
+	}
+	return
+}
+
+// BarReturns programs the values to return from Bar.
+func (fake *FakeImpl) BarReturns(err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.BarFunc = func(arg1 chan *string, arg1Alt4 map[string]interface{}) error {
+		return err
+	}
+}
+
+// FakeImplSingleReturnValCall is a recorded call to FakeImpl.SingleReturnVal.
+type FakeImplSingleReturnValCall struct {
+	Arg string
+}
+
+// SingleReturnVal records the call and returns the programmed values.
+func (fake *FakeImpl) SingleReturnVal(arg string) (retVal int, err error) {
+	fake.mu.Lock()
+	fake.SingleReturnValCalls = append(fake.SingleReturnValCalls, FakeImplSingleReturnValCall{
+		Arg: arg,
+	})
+	fn := fake.SingleReturnValFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(arg)
+	}
+	return
+}
+
+// SingleReturnValReturns programs the values to return from SingleReturnVal.
+func (fake *FakeImpl) SingleReturnValReturns(retVal int, err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.SingleReturnValFunc = func(arg string) (int, error) {
+		return retVal, err
+	}
+}
+
+// FakeImplNoReturnValCall is a recorded call to FakeImpl.NoReturnVal.
+type FakeImplNoReturnValCall struct {
+	Arg int
+}
+
+// NoReturnVal records the call and returns the programmed values.
+func (fake *FakeImpl) NoReturnVal(arg int) (err error) {
+	fake.mu.Lock()
+	fake.NoReturnValCalls = append(fake.NoReturnValCalls, FakeImplNoReturnValCall{
+		Arg: arg,
+	})
+	fn := fake.NoReturnValFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(arg)
+	}
+	return
+}
+
+// NoReturnValReturns programs the values to return from NoReturnVal.
+func (fake *FakeImpl) NoReturnValReturns(err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.NoReturnValFunc = func(arg int) error {
+		return err
+	}
+}
+
+// FakeImplLookupCall is a recorded call to FakeImpl.Lookup.
+type FakeImplLookupCall struct {
+	Key string
+}
+
+// Lookup records the call and returns the programmed values.
+func (fake *FakeImpl) Lookup(key string) (retVal int, retVal1 bool) {
+	fake.mu.Lock()
+	fake.LookupCalls = append(fake.LookupCalls, FakeImplLookupCall{
+		Key: key,
+	})
+	fn := fake.LookupFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(key)
+	}
+	return
+}
+
+// LookupReturns programs the values to return from Lookup.
+func (fake *FakeImpl) LookupReturns(retVal int, retVal1 bool) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.LookupFunc = func(key string) (int, bool) {
+		return retVal, retVal1
+	}
+}
+
+// FakeImplArrayMethodCall is a recorded call to FakeImpl.ArrayMethod.
+type FakeImplArrayMethodCall struct {
+	Sli []*pkg3.Something
+	Arr [32]*pkg3.Something
+}
+
+// ArrayMethod records the call and returns the programmed values.
+func (fake *FakeImpl) ArrayMethod(sli []*pkg3.Something, arr [32]*pkg3.Something) (err error) {
+	fake.mu.Lock()
+	fake.ArrayMethodCalls = append(fake.ArrayMethodCalls, FakeImplArrayMethodCall{
+		Sli: sli,
+		Arr: arr,
+	})
+	fn := fake.ArrayMethodFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(sli, arr)
+	}
+	return
+}
+
+// ArrayMethodReturns programs the values to return from ArrayMethod.
+func (fake *FakeImpl) ArrayMethodReturns(err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.ArrayMethodFunc = func(sli []*pkg3.Something, arr [32]*pkg3.Something) error {
+		return err
+	}
+}
+
+// FakeImplChanMethodCall is a recorded call to FakeImpl.ChanMethod.
+type FakeImplChanMethodCall struct {
+	Chan1 chan *pkg3.Something
+	Chan2 <-chan *pkg3.Something
+	Chan3 chan<- *pkg3.Something
+}
+
+// ChanMethod records the call and returns the programmed values.
+func (fake *FakeImpl) ChanMethod(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.Something, chan3 chan<- *pkg3.Something) (err error) {
+	fake.mu.Lock()
+	fake.ChanMethodCalls = append(fake.ChanMethodCalls, FakeImplChanMethodCall{
+		Chan1: chan1,
+		Chan2: chan2,
+		Chan3: chan3,
+	})
+	fn := fake.ChanMethodFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(chan1, chan2, chan3)
+	}
+	return
+}
+
+// ChanMethodReturns programs the values to return from ChanMethod.
+func (fake *FakeImpl) ChanMethodReturns(err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.ChanMethodFunc = func(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.Something, chan3 chan<- *pkg3.Something) error {
+		return err
+	}
+}
+
+// FakeImplMapMethodCall is a recorded call to FakeImpl.MapMethod.
+type FakeImplMapMethodCall struct {
+	M map[string]*pkg3.Something
+}
+
+// MapMethod records the call and returns the programmed values.
+func (fake *FakeImpl) MapMethod(m map[string]*pkg3.Something) (err error) {
+	fake.mu.Lock()
+	fake.MapMethodCalls = append(fake.MapMethodCalls, FakeImplMapMethodCall{
+		M: m,
+	})
+	fn := fake.MapMethodFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(m)
+	}
+	return
+}
+
+// MapMethodReturns programs the values to return from MapMethod.
+func (fake *FakeImpl) MapMethodReturns(err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.MapMethodFunc = func(m map[string]*pkg3.Something) error {
+		return err
+	}
+}
+
+// FakeImplFooBarBazCall is a recorded call to FakeImpl.FooBarBaz.
+type FakeImplFooBarBazCall struct {
+}
+
+// FooBarBaz records the call and returns the programmed values.
+func (fake *FakeImpl) FooBarBaz() {
+	fake.mu.Lock()
+	fake.FooBarBazCalls = append(fake.FooBarBazCalls, FakeImplFooBarBazCall{})
+	fn := fake.FooBarBazFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		fn()
 	}
+}
 
+// ImplView is a view of Impl which uses a fixed tag. It is safe to use views
+// with different tags concurrently.
+type ImplView struct {
+	merged  *Impl
+	tag     string
+	lastErr import_atomic.Pointer[error]
+}
 
+// WithTag returns a view which uses given tag regardless of the current tag. Unknown tags are
+// treated as the default tag.
+func (merged *Impl) WithTag(tag string) *ImplView {
+	tag, ok := ResolveTagForImpl(tag)
+	if !ok {
+		tag = "v0.0.3"
+	}
+	return &ImplView{merged: merged, tag: tag}
+}
+
+// Tag returns the tag of the view.
+func (view *ImplView) Tag() string {
+	return view.tag
+}
+
+// Supports tells if the implementation of the view tag supports given method.
+func (view *ImplView) Supports(method string) bool {
+	return view.merged.SupportsForTag(view.tag, method)
+}
+
+// LastError returns the error of the last call to a method of the view which does not
+// return an error.
+func (view *ImplView) LastError() error {
+	if err := view.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (view *ImplView) setLastError(err error) {
+	view.lastErr.Store(&err)
+}
+
+// Foo calls the implementation of the view tag.
+func (view *ImplView) Foo(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (retVal *FooOutput, err error) {
 	retVal = &FooOutput{}
 
+	if view.tag == "v0.0.1" {
+		val, methodErr := view.merged.typ0.Foo(arg1)
 
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
 
-	if merged.currTag == "v0.0.1" {
-		val, methodErr := merged.typ0.Foo(arg1)
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+	}
+
+	if view.tag == "v0.0.2" {
+		val, methodErr := view.merged.typ1.Foo(arg1, arg2, arg3)
 
 		if methodErr != nil {
 			err = methodErr
 			return
 		}
 
+		retVal.Value = val
 
-		retVal.A = val.A
+		return
+	}
 
-		retVal.B = val.B
+	if view.tag == "v0.0.3" {
+		val, methodErr := view.merged.typ2.Foo(arg2, arg3Alt2)
 
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
 
 		return
 	}
 
-	if merged.currTag == "v0.0.2" {
-		val, methodErr := merged.typ1.Foo(arg1, arg2, arg3)
+	err = &NotImplementedError{Type: "Impl", Method: "Foo", Tag: view.tag}
+	return
+}
+
+// Bar calls the implementation of the view tag.
+func (view *ImplView) Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error) {
+	if view.tag == "v0.0.1" {
+		view.merged.typ0.Bar(arg1)
+
+		return
+	}
+
+	if view.tag == "v0.0.3" {
+		methodErr := view.merged.typ2.Bar(arg1Alt4)
 
 		if methodErr != nil {
 			err = methodErr
 			return
 		}
 
+		return
+	}
 
-		retVal.Value = val
+	if view.tag == "v0.0.2" {
+		view.merged.typ0.Bar(arg1)
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "Bar", Tag: view.tag}
+	return
+}
 
+// SingleReturnVal calls the implementation of the view tag.
+func (view *ImplView) SingleReturnVal(arg string) (retVal int, err error) {
+	if view.tag == "v0.0.1" {
+		val, methodErr := view.merged.typ0.SingleReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
 
 		return
 	}
 
-	if merged.currTag == "v0.0.3" {
-		val, methodErr := merged.typ2.Foo(arg2, arg3Alt2)
+	if view.tag == "v0.0.2" {
+		val, methodErr := view.merged.typ1.SingleReturnVal(arg)
 
 		if methodErr != nil {
 			err = methodErr
 			return
 		}
 
+		retVal = val
 
-		retVal.ValueAlt3 = val
+		return
+	}
+
+	if view.tag == "v0.0.3" {
+		val, methodErr := view.merged.typ1.SingleReturnVal(arg)
 
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "SingleReturnVal", Tag: view.tag}
+	return
+}
+
+// NoReturnVal calls the implementation of the view tag.
+func (view *ImplView) NoReturnVal(arg int) (err error) {
+	if view.tag == "v0.0.2" {
+		methodErr := view.merged.typ1.NoReturnVal()
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if view.tag == "v0.0.3" {
+		methodErr := view.merged.typ2.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	if view.tag == "v0.0.1" {
+		methodErr := view.merged.typ2.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "NoReturnVal", Tag: view.tag}
+	return
+}
+
+// Lookup calls the implementation of the view tag.
+func (view *ImplView) Lookup(key string) (retVal int, retVal1 bool) {
+	view.lastErr.Store(nil)
+
+	if view.tag == "v0.0.2" {
+		val, val1 := view.merged.typ1.Lookup(key)
+
+		retVal, retVal1 = val, val1
 
 		return
 	}
 
+	if view.tag == "v0.0.3" {
+		val, val1 := view.merged.typ1.Lookup(key)
 
-	err = import_fmt.Errorf("Impl.Foo not implemented (tag=%s)", merged.currTag)
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	view.setLastError(&NotImplementedError{Type: "Impl", Method: "Lookup", Tag: view.tag})
 	return
 }
 
+// ArrayMethod calls the implementation of the view tag.
+func (view *ImplView) ArrayMethod(sli []*pkg3.Something, arr [32]*pkg3.Something) (err error) {
+	if view.tag == "v0.0.3" {
+		methodErr := view.merged.typ2.ArrayMethod(sli, arr)
 
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
 
-// Bar multiplexes to different implementations of the method.
-func (merged *Impl) Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error) {
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "ArrayMethod", Tag: view.tag}
+	return
+}
+
+// ChanMethod calls the implementation of the view tag.
+func (view *ImplView) ChanMethod(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.Something, chan3 chan<- *pkg3.Something) (err error) {
+	if view.tag == "v0.0.3" {
+		methodErr := view.merged.typ2.ChanMethod(chan1, chan2, chan3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "ChanMethod", Tag: view.tag}
+	return
+}
+
+// MapMethod calls the implementation of the view tag.
+func (view *ImplView) MapMethod(m map[string]*pkg3.Something) (err error) {
+	if view.tag == "v0.0.3" {
+		methodErr := view.merged.typ2.MapMethod(m)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		return
+	}
+
+	err = &NotImplementedError{Type: "Impl", Method: "MapMethod", Tag: view.tag}
+	return
+}
+
+// FooBarBaz calls the implementation of the view tag.
+func (view *ImplView) FooBarBaz() {
+	view.lastErr.Store(nil)
+
+	if view.tag == "v0.0.3" {
+		view.merged.typ2.FooBarBaz()
+
+		return
+	}
+
+	view.setLastError(&NotImplementedError{Type: "Impl", Method: "FooBarBaz", Tag: view.tag})
+}
+
+// FooOutput is a merged return type.
+type FooOutput struct {
+	A         string
+	B         float32
+	Value     *pkg2_2.Int
+	ValueAlt3 *big.Int
+}
+
+// Foo multiplexes to different implementations of the method.
+func (merged *Impl) Foo(arg1 string, arg2 int, arg3 map[string]interface{}, arg3Alt2 *big.Int) (retVal *FooOutput, err error) {
 	if !merged.unsafe {
 		merged.mu.RLock()
 		defer merged.mu.RUnlock()
 	}
 
+	retVal = &FooOutput{}
 
+	if merged.currTag == "v0.0.1" {
+		val, methodErr := merged.typ0.Foo(arg1)
 
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
 
-	if merged.currTag == "v0.0.1" {
-		merged.typ0.Bar(arg1)
+		retVal.A = val.A
+		retVal.B = val.B
+
+		return
+	}
+
+	if merged.currTag == "v0.0.2" {
+		val, methodErr := merged.typ1.Foo(arg1, arg2, arg3)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.Value = val
+
+		return
+	}
+
+	if merged.currTag == "v0.0.3" {
+		val, methodErr := merged.typ2.Foo(arg2, arg3Alt2)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal.ValueAlt3 = val
 
+		return
+	}
 
+	err = &NotImplementedError{Type: "Impl", Method: "Foo", Tag: merged.currTag}
+	return
+}
 
+// Bar multiplexes to different implementations of the method.
+func (merged *Impl) Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "v0.0.1" {
+		merged.typ0.Bar(arg1)
 
 		return
 	}
@@ -216,18 +967,19 @@ func (merged *Impl) Bar(arg1 chan *string, arg1Alt4 map[string]interface{}) (err
 			return
 		}
 
+		return
+	}
 
+	if merged.currTag == "v0.0.2" {
+		merged.typ0.Bar(arg1)
 
 		return
 	}
 
-
-	err = import_fmt.Errorf("Impl.Bar not implemented (tag=%s)", merged.currTag)
+	err = &NotImplementedError{Type: "Impl", Method: "Bar", Tag: merged.currTag}
 	return
 }
 
-
-
 // SingleReturnVal multiplexes to different implementations of the method.
 func (merged *Impl) SingleReturnVal(arg string) (retVal int, err error) {
 	if !merged.unsafe {
@@ -235,9 +987,6 @@ func (merged *Impl) SingleReturnVal(arg string) (retVal int, err error) {
 		defer merged.mu.RUnlock()
 	}
 
-
-
-
 	if merged.currTag == "v0.0.1" {
 		val, methodErr := merged.typ0.SingleReturnVal()
 
@@ -264,12 +1013,22 @@ func (merged *Impl) SingleReturnVal(arg string) (retVal int, err error) {
 		return
 	}
 
+	if merged.currTag == "v0.0.3" {
+		val, methodErr := merged.typ1.SingleReturnVal(arg)
 
-	err = import_fmt.Errorf("Impl.SingleReturnVal not implemented (tag=%s)", merged.currTag)
-	return
-}
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
 
+		return
+	}
 
+	err = &NotImplementedError{Type: "Impl", Method: "SingleReturnVal", Tag: merged.currTag}
+	return
+}
 
 // NoReturnVal multiplexes to different implementations of the method.
 func (merged *Impl) NoReturnVal(arg int) (err error) {
@@ -278,9 +1037,6 @@ func (merged *Impl) NoReturnVal(arg int) (err error) {
 		defer merged.mu.RUnlock()
 	}
 
-
-
-
 	if merged.currTag == "v0.0.2" {
 		methodErr := merged.typ1.NoReturnVal()
 
@@ -289,8 +1045,6 @@ func (merged *Impl) NoReturnVal(arg int) (err error) {
 			return
 		}
 
-
-
 		return
 	}
 
@@ -302,17 +1056,52 @@ func (merged *Impl) NoReturnVal(arg int) (err error) {
 			return
 		}
 
+		return
+	}
 
+	if merged.currTag == "v0.0.1" {
+		methodErr := merged.typ2.NoReturnVal(arg)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
 
 		return
 	}
 
-
-	err = import_fmt.Errorf("Impl.NoReturnVal not implemented (tag=%s)", merged.currTag)
+	err = &NotImplementedError{Type: "Impl", Method: "NoReturnVal", Tag: merged.currTag}
 	return
 }
 
+// Lookup multiplexes to different implementations of the method.
+func (merged *Impl) Lookup(key string) (retVal int, retVal1 bool) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	merged.lastErr.Store(nil)
+
+	if merged.currTag == "v0.0.2" {
+		val, val1 := merged.typ1.Lookup(key)
+
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	if merged.currTag == "v0.0.3" {
+		val, val1 := merged.typ1.Lookup(key)
 
+		retVal, retVal1 = val, val1
+
+		return
+	}
+
+	merged.setLastError(&NotImplementedError{Type: "Impl", Method: "Lookup", Tag: merged.currTag})
+	return
+}
 
 // ArrayMethod multiplexes to different implementations of the method.
 func (merged *Impl) ArrayMethod(sli []*pkg3.Something, arr [32]*pkg3.Something) (err error) {
@@ -321,9 +1110,6 @@ func (merged *Impl) ArrayMethod(sli []*pkg3.Something, arr [32]*pkg3.Something)
 		defer merged.mu.RUnlock()
 	}
 
-
-
-
 	if merged.currTag == "v0.0.3" {
 		methodErr := merged.typ2.ArrayMethod(sli, arr)
 
@@ -332,18 +1118,13 @@ func (merged *Impl) ArrayMethod(sli []*pkg3.Something, arr [32]*pkg3.Something)
 			return
 		}
 
-
-
 		return
 	}
 
-
-	err = import_fmt.Errorf("Impl.ArrayMethod not implemented (tag=%s)", merged.currTag)
+	err = &NotImplementedError{Type: "Impl", Method: "ArrayMethod", Tag: merged.currTag}
 	return
 }
 
-
-
 // ChanMethod multiplexes to different implementations of the method.
 func (merged *Impl) ChanMethod(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.Something, chan3 chan<- *pkg3.Something) (err error) {
 	if !merged.unsafe {
@@ -351,9 +1132,6 @@ func (merged *Impl) ChanMethod(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.So
 		defer merged.mu.RUnlock()
 	}
 
-
-
-
 	if merged.currTag == "v0.0.3" {
 		methodErr := merged.typ2.ChanMethod(chan1, chan2, chan3)
 
@@ -362,18 +1140,13 @@ func (merged *Impl) ChanMethod(chan1 chan *pkg3.Something, chan2 <-chan *pkg3.So
 			return
 		}
 
-
-
 		return
 	}
 
-
-	err = import_fmt.Errorf("Impl.ChanMethod not implemented (tag=%s)", merged.currTag)
+	err = &NotImplementedError{Type: "Impl", Method: "ChanMethod", Tag: merged.currTag}
 	return
 }
 
-
-
 // MapMethod multiplexes to different implementations of the method.
 func (merged *Impl) MapMethod(m map[string]*pkg3.Something) (err error) {
 	if !merged.unsafe {
@@ -381,9 +1154,6 @@ func (merged *Impl) MapMethod(m map[string]*pkg3.Something) (err error) {
 		defer merged.mu.RUnlock()
 	}
 
-
-
-
 	if merged.currTag == "v0.0.3" {
 		methodErr := merged.typ2.MapMethod(m)
 
@@ -392,38 +1162,27 @@ func (merged *Impl) MapMethod(m map[string]*pkg3.Something) (err error) {
 			return
 		}
 
-
-
 		return
 	}
 
-
-	err = import_fmt.Errorf("Impl.MapMethod not implemented (tag=%s)", merged.currTag)
+	err = &NotImplementedError{Type: "Impl", Method: "MapMethod", Tag: merged.currTag}
 	return
 }
 
-
-
 // FooBarBaz multiplexes to different implementations of the method.
-func (merged *Impl) FooBarBaz() (err error) {
+func (merged *Impl) FooBarBaz() {
 	if !merged.unsafe {
 		merged.mu.RLock()
 		defer merged.mu.RUnlock()
 	}
 
-
-
+	merged.lastErr.Store(nil)
 
 	if merged.currTag == "v0.0.3" {
 		merged.typ2.FooBarBaz()
 
-
-
-
 		return
 	}
 
-
-	err = import_fmt.Errorf("Impl.FooBarBaz not implemented (tag=%s)", merged.currTag)
-	return
-}
\ No newline at end of file
+	merged.setLastError(&NotImplementedError{Type: "Impl", Method: "FooBarBaz", Tag: merged.currTag})
+}
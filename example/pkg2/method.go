@@ -14,3 +14,14 @@ func (impl *Impl2) SingleReturnVal(arg string) (int, error) {
 func (impl *Impl2) NoReturnVal() error {
 	return nil
 }
+
+func (impl *Impl2) Lookup(key string) (int, bool) {
+	return 0, false
+}
+
+type cursor struct{}
+
+// unexported result type
+func (impl *Impl2) Cursor() (*cursor, error) {
+	return &cursor{}, nil
+}
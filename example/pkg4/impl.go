@@ -0,0 +1,20 @@
+package pkg4
+
+import "math/big"
+
+// Impl4 is a generic implementation which is constructed as a value.
+type Impl4[T any] struct {
+	val   T
+	limit *big.Int
+}
+
+func NewImpl4[T any](arg2 int, opts ...string) (Impl4[T], error) {
+	return Impl4[T]{limit: big.NewInt(int64(arg2))}, nil
+}
+
+// Config is not a valid source type since its constructor does not return an error.
+type Config struct{}
+
+func NewConfig() *Config {
+	return &Config{}
+}
@@ -0,0 +1,35 @@
+package pkg4
+
+// value receiver
+func (impl Impl4[T]) Foo(arg2 int) (T, error) {
+	return impl.val, nil
+}
+
+// pointer receiver
+func (impl *Impl4[T]) NoReturnVal(arg int) error {
+	return nil
+}
+
+func (impl Impl4[T]) Limit() (string, error) {
+	return impl.limit.String(), nil
+}
+
+// grouped params
+func (impl Impl4[T]) Sum(a, b int) (int, error) {
+	return a + b, nil
+}
+
+// unnamed params
+func (impl *Impl4[T]) Store(T, string) error {
+	return nil
+}
+
+// variadic params
+func (impl *Impl4[T]) Append(prefix string, vals ...T) error {
+	return nil
+}
+
+// more values than pkg2.Impl2.Lookup
+func (impl Impl4[T]) Lookup(key string) (T, bool, error) {
+	return impl.val, false, nil
+}
@@ -0,0 +1,135 @@
+package merge
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/forta-network/go-merge-types/semver"
+)
+
+// Fallback policies
+const (
+	FallbackPolicyNone         = "none"
+	FallbackPolicyNearestOlder = "nearestOlder"
+)
+
+// addFallbacks adds variations to the methods for the tags which lack them, so that
+// the calls are dispatched statically to the fallback implementations.
+func addFallbacks(config *MergeConfig, methods []*Method) error {
+	fallback := config.Output.Fallback
+
+	switch fallback.Policy {
+	case "", FallbackPolicyNone, FallbackPolicyNearestOlder:
+	default:
+		return fmt.Errorf("%w: unknown policy %q", ErrInvalidFallback, fallback.Policy)
+	}
+
+	// the sources can be listed in any order: the older ones are found by the versions
+	var versions []semver.Version
+	if fallback.Policy == FallbackPolicyNearestOlder {
+		var err error
+		if versions, err = sourceVersions(config.Sources); err != nil {
+			return err
+		}
+	}
+
+	// check the explicit lists in a stable order
+	methodNames := make([]string, 0, len(fallback.Methods))
+	for methodName := range fallback.Methods {
+		methodNames = append(methodNames, methodName)
+	}
+	sort.Strings(methodNames)
+	for _, methodName := range methodNames {
+		if findMethod(methods, methodName) == nil {
+			return fallback.methodPos[methodName].wrap(fmt.Errorf("%w: unknown method %s", ErrInvalidFallback, methodName))
+		}
+		for _, tag := range fallback.Methods[methodName] {
+			if findSourceIndex(config.Sources, tag) < 0 {
+				return fmt.Errorf("%w: unknown tag %q for method %s", ErrInvalidFallback, tag, methodName)
+			}
+		}
+	}
+
+	for _, method := range methods {
+		variations := make([]*Variation, len(config.Sources))
+		for _, variation := range method.Variations {
+			variations[variation.SourceIndex] = variation
+		}
+
+		for i, source := range config.Sources {
+			if variations[i] != nil {
+				continue
+			}
+
+			var found *Variation
+			tags, ok := fallback.Methods[method.Name]
+			switch {
+			case ok:
+				// use the first tag from the list which implements the method
+				for _, tag := range tags {
+					if found = variations[findSourceIndex(config.Sources, tag)]; found != nil {
+						break
+					}
+				}
+
+			case fallback.Policy == FallbackPolicyNearestOlder:
+				found = nearestOlder(versions, variations, i)
+			}
+			if found == nil {
+				continue
+			}
+
+			variation := *found
+			variation.Tag = source.Tag
+			variation.Fallback = true
+			method.Variations = append(method.Variations, &variation)
+		}
+	}
+
+	return nil
+}
+
+// sourceVersions parses the source tags for the nearestOlder policy.
+func sourceVersions(sources []*Source) ([]semver.Version, error) {
+	versions := make([]semver.Version, len(sources))
+	for i, source := range sources {
+		version, err := semver.Parse(source.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s policy needs semantic version tags: %v", ErrInvalidFallback, FallbackPolicyNearestOlder, err)
+		}
+		versions[i] = version
+	}
+	return versions, nil
+}
+
+// nearestOlder finds the variation of the newest source which is older than the source.
+func nearestOlder(versions []semver.Version, variations []*Variation, sourceIndex int) *Variation {
+	var found *Variation
+	for j, variation := range variations {
+		if variation == nil || versions[j].Compare(versions[sourceIndex]) >= 0 {
+			continue
+		}
+		if found == nil || versions[j].Compare(versions[found.SourceIndex]) > 0 {
+			found = variation
+		}
+	}
+	return found
+}
+
+func findMethod(methods []*Method, name string) *Method {
+	for _, method := range methods {
+		if method.Name == name {
+			return method
+		}
+	}
+	return nil
+}
+
+func findSourceIndex(sources []*Source, tag string) int {
+	for i, source := range sources {
+		if source.Tag == tag {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,249 @@
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// TargetInterface is an existing interface which the output type should implement.
+type TargetInterface struct {
+	Package   *packages.Package
+	Object    *types.TypeName
+	Interface *types.Interface
+}
+
+// FindInterface finds the named interface in given package.
+func FindInterface(pkg *packages.Package, name string) (*TargetInterface, error) {
+	obj, ok := pkg.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s.%s", ErrInterfaceNotFound, pkg.PkgPath, name)
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s.%s is not an interface", ErrInterfaceNotFound, pkg.PkgPath, name)
+	}
+	return &TargetInterface{Package: pkg, Object: obj, Interface: iface}, nil
+}
+
+func loadTargetInterface(loader *Loader, config *MergeConfig) (*TargetInterface, error) {
+	implements := config.Output.Implements
+	if len(implements.ImportPath) == 0 {
+		return nil, nil
+	}
+	pkg, err := loader.Load(config.BaseDir, &Package{ImportPath: implements.ImportPath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the interface package: %w", err)
+	}
+	return FindInterface(pkg, implements.Name)
+}
+
+// isOutputPackage tells if the interface is declared in the output package.
+func (target *TargetInterface) isOutputPackage(config *MergeConfig) bool {
+	if len(target.Package.GoFiles) == 0 {
+		return false
+	}
+	outDir, err := filepath.Abs(filepath.Dir(filepath.Join(config.BaseDir, config.Output.File)))
+	if err != nil {
+		return false
+	}
+	return filepath.Dir(target.Package.GoFiles[0]) == outDir
+}
+
+// implementInterface shapes the merged methods after the target interface methods and
+// reports the interface methods which no source can provide.
+func implementInterface(config *MergeConfig, sourceImpls []*SourceImplementation, target *TargetInterface, methods []*Method, qualifier types.Qualifier) error {
+	generated := generatedMethods(config)
+	rewriter := config.Output.Rewrite
+
+	var problems []string
+	for i := 0; i < target.Interface.NumMethods(); i++ {
+		ifaceMethod := target.Interface.Method(i)
+		if generated[ifaceMethod.Name()] {
+			continue
+		}
+		var method *Method
+		for _, m := range methods {
+			if rewriter.Rewrite(m.Name) == ifaceMethod.Name() {
+				method = m
+				break
+			}
+		}
+		if method == nil {
+			problems = append(problems, fmt.Sprintf("%s: no source has the method", ifaceMethod.Name()))
+			continue
+		}
+		method.Name = ifaceMethod.Name()
+		if err := shapeMethod(config, sourceImpls, method, ifaceMethod.Type().(*types.Signature), qualifier); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", ifaceMethod.Name(), err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s.%s:\n\t%s", ErrInterfaceNotSatisfied,
+			target.Object.Pkg().Path(), target.Object.Name(), strings.Join(problems, "\n\t"))
+	}
+
+	config.Output.Implements.TypeName = types.TypeString(target.Object.Type(), qualifier)
+	return nil
+}
+
+// generatedMethods are the methods of the output type which do not come from the sources.
+func generatedMethods(config *MergeConfig) map[string]bool {
+	generated := map[string]bool{
+		"Use":            true,
+		"Unsafe":         true,
+		"Safe":           true,
+		"Supports":       true,
+		"SupportsForTag": true,
+	}
+	if config.Output.NoError.Policy == NoErrorPolicyLastError {
+		generated["LastError"] = true
+	}
+	if len(config.Output.View.Name) > 0 {
+		generated["WithTag"] = true
+	}
+	return generated
+}
+
+// shapeMethod makes the method signature same with the interface method signature and
+// keeps only the variations which are compatible with it. The dropped variations are
+// reported as warnings.
+func shapeMethod(config *MergeConfig, sourceImpls []*SourceImplementation, method *Method, signature *types.Signature, qualifier types.Qualifier) error {
+	results := signature.Results()
+	valueCount := results.Len()
+	noError := true
+	if valueCount > 0 && isErrorType(results.At(valueCount-1).Type()) {
+		valueCount--
+		noError = false
+	}
+	policy := config.Output.NoError.Policy
+	if noError && (len(policy) == 0 || policy == NoErrorPolicyNone) {
+		return errors.New("does not return an error: a noError policy is required")
+	}
+
+	var fields []*Field
+	for j := 0; j < valueCount; j++ {
+		field := convertField(qualifier, -1, results.At(j))
+		field.Name = resultName(results, j)
+		fields = append(fields, field)
+	}
+	args := convertParams(qualifier, -1, signature)
+
+	var variations []*Variation
+	for _, variation := range method.Variations {
+		var reason string
+		switch {
+		// the errors cannot be returned if the interface method does not return one
+		case noError && !variation.NoError:
+			reason = "returns an error"
+		case !matchValues(variation.ValueTypes, fields):
+			reason = fmt.Sprintf("returns (%s) instead of (%s)",
+				strings.Join(variation.ValueTypes, ", "), joinFieldTypes(fields))
+		case !matchArgs(variation.Args, args):
+			reason = fmt.Sprintf("takes (%s) instead of (%s)",
+				joinFieldTypes(variation.Args), joinFieldTypes(args))
+		default:
+			variations = append(variations, variation)
+			continue
+		}
+		if variation.Fallback {
+			reason += " as the fallback of " + variation.Tag
+		}
+		warnIncompatible(config, sourceImpls, variation, fmt.Errorf("%w %s: %s - ignoring",
+			ErrIncompatibleVariation, types.TypeString(signature, qualifier), reason))
+	}
+	if len(variations) == 0 {
+		return fmt.Errorf("no source has a compatible variation of %s", types.TypeString(signature, qualifier))
+	}
+
+	method.Implements = true
+	method.Variations = variations
+	method.Args = args
+	method.ReturnType.Fields = fields
+	method.NoReturn = valueCount == 0
+	method.SingleReturn = valueCount == 1
+	method.MultiReturn = valueCount > 1
+	if method.SingleReturn {
+		method.ReturnType.Name = fields[0].Type
+	}
+	method.NoError = noError
+	method.NoErrorPolicy = ""
+	if noError {
+		method.NoErrorPolicy = policy
+	}
+	return nil
+}
+
+// warnIncompatible reports a variation which does not fit the interface method at the
+// source method.
+func warnIncompatible(config *MergeConfig, sourceImpls []*SourceImplementation, variation *Variation, err error) {
+	sourceImpl := sourceImpls[variation.SourceIndex]
+	var pos token.Pos
+	for _, sourceMethod := range sourceImpl.Methods {
+		if sourceMethod.Name() == variation.Name {
+			pos = sourceMethod.Pos()
+			break
+		}
+	}
+	config.Warnings = append(config.Warnings, newSourceError(variation.SourceIndex, sourceImpl.Package, variation.Name, pos, err))
+}
+
+func joinFieldTypes(fields []*Field) string {
+	fieldTypes := make([]string, len(fields))
+	for i, field := range fields {
+		fieldTypes[i] = field.Type
+	}
+	return strings.Join(fieldTypes, ", ")
+}
+
+func matchValues(valueTypes []string, fields []*Field) bool {
+	if len(valueTypes) != len(fields) {
+		return false
+	}
+	for j, valueType := range valueTypes {
+		if valueType != fields[j].Type {
+			return false
+		}
+	}
+	return true
+}
+
+// matchArgs matches the variation args with the method args of the same type, preferring
+// the same names, and renames them to be forwarded.
+func matchArgs(from, to []*Field) bool {
+	matched := make([]*Field, len(from))
+	used := make(map[*Field]bool)
+	for i, arg := range from {
+		for _, param := range to {
+			if !used[param] && param.Name == arg.Name && param.Type == arg.Type {
+				matched[i] = param
+				used[param] = true
+				break
+			}
+		}
+	}
+	for i, arg := range from {
+		if matched[i] != nil {
+			continue
+		}
+		for _, param := range to {
+			if !used[param] && param.Type == arg.Type {
+				matched[i] = param
+				used[param] = true
+				break
+			}
+		}
+		if matched[i] == nil {
+			return false
+		}
+	}
+	for i, arg := range from {
+		arg.Name = matched[i].Name
+	}
+	return true
+}
@@ -4,33 +4,105 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
 	"go/types"
-	"log"
 	"os"
-	"reflect"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/forta-network/go-merge-types/semver"
 	"github.com/forta-network/go-merge-types/utils"
-	"gopkg.in/yaml.v3"
+	"golang.org/x/tools/go/packages"
 )
 
+// Run generates the code for a config file with a single merge target.
 func Run(configPath string) (*MergeConfig, []byte, error) {
-	b, err := os.ReadFile(configPath)
+	configs, err := ReadConfig(configPath)
 	if err != nil {
 		return nil, nil, err
 	}
+	if len(configs) != 1 {
+		return nil, nil, fmt.Errorf("expected a single merge target in %s but found %d", configPath, len(configs))
+	}
+	config := configs[0]
+
+	b, err := Generate(config)
+	if err != nil {
+		return config, nil, err
+	}
+
+	return config, b, nil
+}
+
+// RunAll generates the code for all merge targets in a config file. The source packages
+// are loaded once and shared between the targets.
+func RunAll(configPath string) ([]*Target, error) {
+	configs, err := ReadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := NewLoader()
+	var targets []*Target
+	packageFiles := make(map[string][]*File)
+	for _, config := range configs {
+		// the earlier targets of the output package are not written yet
+		outputDir := path.Dir(path.Join(config.BaseDir, config.Output.File))
+		config.Output.PackageFiles = packageFiles[outputDir]
+
+		target := &Target{Config: config}
+		targets = append(targets, target)
+		target.Code, err = GenerateWithLoader(loader, config)
+		if err != nil {
+			return targets, fmt.Errorf("failed to generate %s: %w", config.Output.Type, err)
+		}
+		packageFiles[outputDir] = append(packageFiles[outputDir], target.Files()...)
+	}
+
+	return targets, nil
+}
+
+// ReadConfig reads the merge targets from a config file. The top-level sources and output
+// make the first target, if specified.
+func ReadConfig(configPath string) ([]*MergeConfig, error) {
+	b, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the config is validated before loading any packages
+	file, err := parseConfig(configPath, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []*MergeConfig
+	if len(file.Sources) > 0 {
+		configs = append(configs, &file.MergeConfig)
+	}
+	configs = append(configs, file.Targets...)
 
-	var config MergeConfig
-	if err := yaml.Unmarshal(b, &config); err != nil {
-		return &config, nil, err
+	for _, config := range configs {
+		prepareConfig(configPath, config)
 	}
 
+	return configs, nil
+}
+
+func prepareConfig(configPath string, config *MergeConfig) {
+	// resolve source packages with respect to the config path
+	config.BaseDir = path.Dir(configPath)
+
 	for _, source := range config.Sources {
 		// fix package source dirs relative to the config path
-		source.Package.SourceDir = utils.RelativePath(configPath, source.Package.SourceDir)
+		if len(source.Package.SourceDir) > 0 {
+			source.Package.SourceDir = utils.RelativePath(configPath, source.Package.SourceDir)
+		}
 		// update known tags
 		config.Output.KnownTags = append(config.Output.KnownTags, source.Tag)
 	}
@@ -42,142 +114,324 @@ func Run(configPath string) (*MergeConfig, []byte, error) {
 			break
 		}
 	}
+}
+
+func Generate(config *MergeConfig) ([]byte, error) {
+	return GenerateWithLoader(NewLoader(), config)
+}
+
+// GenerateWithLoader generates the code by using the source packages from the loader.
+func GenerateWithLoader(loader *Loader, config *MergeConfig) ([]byte, error) {
+	// the config can be generated again
+	resetGenerated(config)
 
-	b, err = Generate(&config)
+	// the errors are declared once in an output package
+	sharedErrors, err := sharesErrors(config)
 	if err != nil {
-		return &config, nil, err
+		return nil, err
 	}
+	config.Output.SharedErrors = sharedErrors
 
-	return &config, b, nil
-}
+	// the configs which are not read from a file are not validated yet
+	for i, rule := range config.Output.Rewrite {
+		if err := rule.Compile(); err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidRewrite, i, err)
+		}
+	}
 
-func Generate(config *MergeConfig) ([]byte, error) {
-	var pkgs []*ast.Package
-	for _, source := range config.Sources {
-		pkgs = append(pkgs, LoadPackage(source.Package.SourceDir))
+	if err := checkRanges(config); err != nil {
+		return nil, err
 	}
 
 	var impls []*SourceImplementation
-	for i, pkg := range pkgs {
-		impls = append(impls, FindImplementation(pkg, config.Sources[i].Type))
+	for i, source := range config.Sources {
+		pkg, err := loader.Load(config.BaseDir, &source.Package)
+		if err != nil {
+			return nil, &SourceError{
+				SourceIndex: i,
+				Package:     packageName(source.Package.SourceDir, &source.Package),
+				Err:         err,
+			}
+		}
+		// import path is optional if the source dir is specified
+		if len(source.Package.ImportPath) == 0 {
+			source.Package.ImportPath = pkg.PkgPath
+		}
+		impl, err := FindImplementation(i, pkg, source)
+		if err != nil {
+			return nil, err
+		}
+		impls = append(impls, impl)
 	}
 
-	return mergeAndGenerate(config, impls)
-}
+	target, err := loadTargetInterface(loader, config)
+	if err != nil {
+		return nil, err
+	}
 
-func LoadPackage(pkgDir string) *ast.Package {
-	fset := token.NewFileSet()
-	foundPkgs, err := parser.ParseDir(fset, pkgDir, nil, parser.ParseComments)
+	code, err := mergeAndGenerate(config, impls, target)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	for _, p := range foundPkgs {
-		return p
+
+	// the output should compile with the source packages
+	if err := checkOutput(config, impls, code); err != nil {
+		return nil, err
 	}
-	log.Fatalf("no package found at: %s", pkgDir)
-	return nil
+	return code, nil
 }
 
-type SourceImplementation struct {
-	Package     *ast.Package
-	Object      *ast.Object
-	Constructor *ast.FuncDecl
-	Methods     []*ast.FuncDecl
-	Types       []*ast.TypeSpec
-	Imports     []*ast.ImportSpec
+// resetGenerated clears what the previous generation has computed in the config.
+func resetGenerated(config *MergeConfig) {
+	config.Warnings = nil
+	for _, source := range config.Sources {
+		source.InitArgs = nil
+		source.ImplType = ""
+		source.ConstructorTypeArgs = ""
+		source.RangeSets = nil
+	}
+	config.Output.HasRanges = false
+	config.Output.InitArgs = nil
+	config.Output.Methods = nil
+	config.Output.TagMethods = nil
+	config.Output.Imports = nil
+	config.Output.ExtraFiles = nil
+	config.Output.Implements.TypeName = ""
 }
 
-func (sourceImpl *SourceImplementation) GetType(expr ast.Expr) (*ast.TypeSpec, bool) {
-	searchType := deref(typeString("", "", expr))
-	for _, typ := range sourceImpl.Types {
-		if typ.Name.Name == searchType {
-			return typ, true
+// checkRanges makes sure that the source version ranges are valid and that
+// each version can match only one source.
+func checkRanges(config *MergeConfig) error {
+	ranges := make([]*semver.Range, len(config.Sources))
+	for i, source := range config.Sources {
+		if len(source.Range) == 0 {
+			continue
+		}
+		rng, err := semver.ParseRange(source.Range)
+		if err != nil {
+			return &SourceError{
+				SourceIndex: i,
+				Package:     source.Package.ImportPath,
+				Err:         fmt.Errorf("%w: %v", ErrInvalidRange, err),
+			}
+		}
+		// the source would never be used for the versions in the range
+		if rng.Unsatisfiable() {
+			return &SourceError{
+				SourceIndex: i,
+				Package:     source.Package.ImportPath,
+				Err:         fmt.Errorf("%w: %q matches no version", ErrInvalidRange, source.Range),
+			}
+		}
+		for j, other := range ranges[:i] {
+			if other != nil && rng.Overlaps(other) {
+				return fmt.Errorf(
+					"%w: %q (%s) and %q (%s)", ErrOverlappingRanges,
+					config.Sources[j].Range, config.Sources[j].Tag, source.Range, source.Tag,
+				)
+			}
 		}
+		ranges[i] = rng
+		source.RangeSets = rng.Sets()
+		config.Output.HasRanges = true
 	}
-	return nil, false
+
+	// the exact tags are resolved before the ranges: the same version would resolve
+	// to a different source with and without the "v" prefix
+	for i, source := range config.Sources {
+		for j, rng := range ranges {
+			if i != j && rng != nil && rng.Contains(source.Tag) {
+				return fmt.Errorf(
+					"%w: tag %q is in %q (%s)", ErrOverlappingRanges,
+					source.Tag, config.Sources[j].Range, config.Sources[j].Tag,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// Loader loads the source packages and keeps them for reuse.
+type Loader struct {
+	pkgs map[loaderKey]*packages.Package
+}
+
+type loaderKey struct {
+	dir     string
+	pattern string
+}
+
+// NewLoader creates a new loader.
+func NewLoader() *Loader {
+	return &Loader{pkgs: make(map[loaderKey]*packages.Package)}
 }
 
-func (sourceImpl *SourceImplementation) GetImport(input string) (string, bool) {
-	parts := strings.Split(input, ".")
-	if len(parts) == 2 {
-		input = strings.Trim(parts[0], "*")
+// Load loads the package or returns it if it was loaded before.
+func (loader *Loader) Load(baseDir string, pkg *Package) (*packages.Package, error) {
+	var key loaderKey
+	key.dir, key.pattern = loadArgs(baseDir, pkg)
+	if foundPkg, ok := loader.pkgs[key]; ok {
+		return foundPkg, nil
 	}
+	foundPkg, err := LoadPackage(baseDir, pkg)
+	if err != nil {
+		return nil, err
+	}
+	loader.pkgs[key] = foundPkg
+	return foundPkg, nil
+}
 
-	for _, imp := range sourceImpl.Imports {
-		// find by name (alias)
-		if imp.Name != nil && imp.Name.Name == input {
-			return fmt.Sprintf("%s %s", imp.Name.Name, imp.Path.Value), true
-		}
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports |
+	packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo
 
-		// find from path
-		parts := strings.Split(strings.Trim(imp.Path.Value, `"`), "/")
-		altName := parts[len(parts)-1]
-		if altName == input {
-			return imp.Path.Value, true
+// LoadPackage loads and type-checks a source package. The package is resolved from
+// the import path through the module graph, unless a source dir is specified.
+func LoadPackage(baseDir string, pkg *Package) (*packages.Package, error) {
+	dir, pattern := loadArgs(baseDir, pkg)
+	foundPkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir}, pattern)
+	// the driver fails if the dir does not exist
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrPackageNotFound, packageName(dir, pkg), err)
+	}
+	if len(foundPkgs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPackageNotFound, packageName(dir, pkg))
+	}
+	foundPkg := foundPkgs[0]
+	// the first error is enough to tell what went wrong
+	if len(foundPkg.Errors) > 0 {
+		pkgErr := foundPkg.Errors[0]
+		// the list errors tell that the package could not be resolved
+		if pkgErr.Kind == packages.ListError {
+			return nil, fmt.Errorf("%w: %s: %w", ErrPackageNotFound, packageName(dir, pkg), pkgErr)
 		}
+		return nil, pkgErr
+	}
+	return foundPkg, nil
+}
+
+// packageName names the package by the import path or by the source dir.
+func packageName(dir string, pkg *Package) string {
+	if len(pkg.ImportPath) > 0 {
+		return pkg.ImportPath
+	}
+	return dir
+}
+
+func loadArgs(baseDir string, pkg *Package) (dir, pattern string) {
+	if len(pkg.SourceDir) > 0 {
+		return pkg.SourceDir, "."
 	}
+	return baseDir, pkg.ImportPath
+}
+
+type SourceImplementation struct {
+	Package     *packages.Package
+	Object      *types.TypeName
+	Type        *types.Named // instantiated if the implementation is generic
+	Constructor *types.Func
+	// ConstructorSig is the (instantiated) constructor signature.
+	ConstructorSig *types.Signature
+	// Result is the implementation type returned by the constructor: a pointer or a value.
+	Result  types.Type
+	Methods []*types.Func
+}
 
-	return "", false
+// IsLocal tells if given type is declared in the source package.
+func (sourceImpl *SourceImplementation) IsLocal(typ types.Type) bool {
+	named, ok := derefType(typ).(*types.Named)
+	return ok && named.Obj().Pkg() == sourceImpl.Package.Types
 }
 
-func FindImplementation(pkg *ast.Package, implName string) *SourceImplementation {
+func FindImplementation(sourceIndex int, pkg *packages.Package, source *Source) (*SourceImplementation, error) {
 	var impl SourceImplementation
 	impl.Package = pkg
 
-	constructorName := fmt.Sprintf("New%s", implName)
-
-	for _, file := range pkg.Files {
-		// collect all imports from all files
-		impl.Imports = append(impl.Imports, file.Imports...)
+	implName := source.Type
+	scope := pkg.Types.Scope()
 
-		if file.Scope != nil {
-			var ok bool
-			obj, ok := file.Scope.Objects[implName]
-			if ok && impl.Object == nil {
-				impl.Object = obj
-			}
-		}
+	obj, ok := scope.Lookup(implName).(*types.TypeName)
+	if !ok {
+		return nil, newSourceError(sourceIndex, pkg, implName, token.NoPos, ErrImplementationNotFound)
 	}
-	if impl.Object == nil {
-		log.Fatalf("implementation not found in %s", pkg.Name)
-		return nil
+	impl.Object = obj
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, newSourceError(sourceIndex, pkg, implName, obj.Pos(), ErrImplementationNotFound)
 	}
 
-	for _, file := range pkg.Files {
-		for _, decl := range file.Decls {
-			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-				// find the constructor
-				if funcDecl.Recv == nil && funcDecl.Name.Name == constructorName {
-					impl.Constructor = funcDecl
-					continue
-				}
-
-				// find the implemented methods
-				if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-					recvList := funcDecl.Recv.List
-					if recvList[0].Type.(*ast.StarExpr).X.(*ast.Ident).Name == implName {
-						impl.Methods = append(impl.Methods, funcDecl)
-					}
-				}
-			}
+	// instantiate generic implementations with the configured type arguments
+	typeArgs, err := evalTypeArgs(pkg, obj.Pos(), source.TypeArgs)
+	if err != nil {
+		return nil, newSourceError(sourceIndex, pkg, implName, obj.Pos(), fmt.Errorf("%w: %v", ErrInvalidTypeArgs, err))
+	}
+	if named.TypeParams().Len() != len(typeArgs) {
+		return nil, newSourceError(sourceIndex, pkg, implName, obj.Pos(), fmt.Errorf(
+			"%w: expected %d type arguments, got %d", ErrInvalidTypeArgs, named.TypeParams().Len(), len(typeArgs)))
+	}
+	impl.Type = named
+	if len(typeArgs) > 0 {
+		inst, err := types.Instantiate(nil, named, typeArgs, true)
+		if err != nil {
+			return nil, newSourceError(sourceIndex, pkg, implName, obj.Pos(), fmt.Errorf("%w: %v", ErrInvalidTypeArgs, err))
 		}
+		impl.Type = inst.(*types.Named)
+	}
 
-		for _, object := range file.Scope.Objects {
-			if typeDecl, ok := object.Decl.(*ast.TypeSpec); ok {
-				impl.Types = append(impl.Types, typeDecl)
-			}
+	// find the constructor
+	constructorName := fmt.Sprintf("New%s", implName)
+	impl.Constructor, ok = scope.Lookup(constructorName).(*types.Func)
+	if !ok {
+		return nil, newSourceError(sourceIndex, pkg, constructorName, obj.Pos(), ErrConstructorNotFound)
+	}
+	impl.ConstructorSig = impl.Constructor.Type().(*types.Signature)
+	if impl.ConstructorSig.TypeParams().Len() > 0 {
+		// generic constructors take the same type arguments as the implementation
+		inst, err := types.Instantiate(nil, impl.ConstructorSig, typeArgs, true)
+		if err != nil {
+			return nil, newSourceError(sourceIndex, pkg, constructorName, impl.Constructor.Pos(), fmt.Errorf("%w: %v", ErrInvalidTypeArgs, err))
 		}
+		impl.ConstructorSig = inst.(*types.Signature)
 	}
 
-	if impl.Constructor == nil {
-		log.Fatalf("constructor %s was not found for type %s in package %s", constructorName, implName, pkg.Name)
-		return nil
+	// the constructor should return the implementation as a pointer or a value, and an error
+	results := impl.ConstructorSig.Results()
+	if results.Len() != 2 || !isErrorType(results.At(1).Type()) ||
+		!types.Identical(derefType(results.At(0).Type()), impl.Type) {
+		return nil, newSourceError(sourceIndex, pkg, constructorName, impl.Constructor.Pos(), ErrUnsupportedConstructor)
+	}
+	impl.Result = results.At(0).Type()
+
+	// find the implemented methods in declaration order: the method set of the pointer type
+	// includes both the value and the pointer receiver methods.
+	for i := 0; i < impl.Type.NumMethods(); i++ {
+		method := impl.Type.Method(i)
+		// unexported methods are not accessible from the output package
+		if method.Exported() {
+			impl.Methods = append(impl.Methods, method)
+		}
 	}
 
-	return &impl
+	return &impl, nil
 }
 
-func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation) ([]byte, error) {
+// evalTypeArgs evaluates the type argument expressions in the scope of the file
+// which declares the implementation so that its imports can be referred to.
+func evalTypeArgs(pkg *packages.Package, pos token.Pos, exprs []string) ([]types.Type, error) {
+	var typeArgs []types.Type
+	for _, expr := range exprs {
+		tv, err := types.Eval(pkg.Fset, pkg.Types, pos, expr)
+		if err != nil {
+			return nil, err
+		}
+		if !tv.IsType() {
+			return nil, fmt.Errorf("%s is not a type", expr)
+		}
+		typeArgs = append(typeArgs, tv.Type)
+	}
+	return typeArgs, nil
+}
+
+func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation, target *TargetInterface) ([]byte, error) {
 	// fix empty package aliases: find package name from ast and append source index i to the name.
 	for i, source := range config.Sources {
 		if len(source.Package.Alias) > 0 {
@@ -187,16 +441,30 @@ func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation)
 		source.Package.Alias = fmt.Sprintf("%s_%d", sourceImpl.Package.Name, i+1)
 	}
 
+	// keep track of the packages referred from the output
+	imports := newImportSet(config.Sources)
+	if target != nil && target.isOutputPackage(config) {
+		imports.local = target.Package.PkgPath
+	}
+
 	// find output type init args
+	alt := &altSuffixes{}
 	for i, sourceImpl := range sourceImpls {
-		params := sourceImpl.Constructor.Type.Params
-		if params == nil {
-			continue
+		qualifier := imports.Qualifier
+
+		// the source type is stored as returned from the constructor
+		source := config.Sources[i]
+		source.ImplType = types.TypeString(sourceImpl.Result, qualifier)
+		if sourceImpl.Constructor.Type().(*types.Signature).TypeParams().Len() > 0 {
+			typeArgs := sourceImpl.Type.TypeArgs()
+			var typeArgStrs []string
+			for j := 0; j < typeArgs.Len(); j++ {
+				typeArgStrs = append(typeArgStrs, types.TypeString(typeArgs.At(j), qualifier))
+			}
+			source.ConstructorTypeArgs = fmt.Sprintf("[%s]", strings.Join(typeArgStrs, ", "))
 		}
-		pkgName := config.Sources[i].Package.Alias
-		for _, param := range params.List {
-			foundParam, ok := isNewParam(pkgName, i, param, config.Output.InitArgs)
-			foundParam.SourceIndex = i
+		for _, param := range convertParams(qualifier, i, sourceImpl.ConstructorSig) {
+			foundParam, ok := isNewParam(alt, param, config.Output.InitArgs)
 			if ok {
 				config.Output.InitArgs = append(config.Output.InitArgs, foundParam)
 			}
@@ -212,46 +480,62 @@ func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation)
 
 	for i, sourceImpl := range sourceImpls {
 		pkgName := config.Sources[i].Package.Alias
+		qualifier := imports.Qualifier
 
 		for _, sourceMethod := range sourceImpl.Methods {
 			// create a method variation
 			var variation Variation
 			variation.SourceIndex = i
 			variation.Tag = config.Sources[i].Tag
-			methodName := sourceMethod.Name.Name
+			methodName := sourceMethod.Name()
 			variation.Name = methodName
 
 			// find out variation method return type
 
-			var resultsList []*ast.Field
-			if sourceMethod.Type.Results != nil {
-				resultsList = sourceMethod.Type.Results.List
+			signature := sourceMethod.Type().(*types.Signature)
+			results := signature.Results()
+
+			// results with unexported types cannot be referred from the output package
+			if v, ok := findUnexportedResult(results); ok {
+				config.Warnings = append(config.Warnings, newSourceError(
+					i, sourceImpl.Package, methodName, sourceMethod.Pos(),
+					fmt.Errorf("%w: unexported result type %s - ignoring", ErrUnsupportedReturn,
+						types.TypeString(v.Type(), types.RelativeTo(sourceImpl.Package.Types))),
+				))
+				continue
+			}
+
+			// the values are optionally followed by an error
+			valueCount := results.Len()
+			if valueCount > 0 && isErrorType(results.At(valueCount-1).Type()) {
+				valueCount--
+			} else {
+				variation.NoError = true
 			}
 
-			var ret *ast.Field
-			switch len(resultsList) {
-			case 0:
+			var ret *types.Var
+			switch {
+			case results.Len() == 0:
 				variation.NoReturn = true
 
-			case 1:
-				v := resultsList[0]
-				if types.ExprString(v.Type) == "error" {
-					variation.OnlyError = true
-				} else {
-					ret = v
-				}
+			case valueCount == 0:
+				variation.OnlyError = true
 
-			case 2:
-				v1, v2 := resultsList[0], resultsList[1]
-				if types.ExprString(v2.Type) != "error" {
-					log.Printf("warning: expected %s.%s.%s to return (<any>, error) - ignoring\n", pkgName, sourceImpl.Object.Name, methodName)
-					continue
-				}
-				ret = v1
+			case valueCount == 1:
+				ret = results.At(0)
+				variation.Values = []string{"val"}
+				variation.ValueTypes = []string{types.TypeString(ret.Type(), qualifier)}
 
 			default:
-				log.Printf("warning: %s.%s.%s has unsupported return list - ignoring\n", pkgName, sourceImpl.Object.Name, methodName)
-				continue
+				// multiple values are merged into the output struct one by one
+				variation.MultiReturn = true
+				for j := 0; j < valueCount; j++ {
+					variation.Values = append(variation.Values, valueName(j))
+					variation.ValueTypes = append(variation.ValueTypes, types.TypeString(results.At(j).Type(), qualifier))
+					field := convertField(qualifier, i, results.At(j))
+					field.Name = resultName(results, j)
+					variation.ReturnedFields = append(variation.ReturnedFields, field)
+				}
 			}
 
 			// find the bucket (merged) method
@@ -277,53 +561,37 @@ func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation)
 			method.Variations = append(method.Variations, &variation)
 
 			// set args
-			for _, param := range sourceMethod.Type.Params.List {
-				field := convertField(pkgName, i, param)
-				field.SourceIndex = i
-				variation.Args = append(variation.Args, field)
-			}
+			variation.Args = convertParams(qualifier, i, signature)
 
 			if ret == nil {
 				continue
 			}
 
 			// anonymous struct
-			structType, ok := ret.Type.(*ast.StructType)
+			structType, ok := ret.Type().(*types.Struct)
 			if ok {
 				variation.MergeReturnedStruct = true
-				for _, param := range structType.Fields.List {
-					variation.ReturnedFields = append(variation.ReturnedFields, &Field{
-						SourceIndex: i,
-						Name:        param.Names[0].Name,
-						Type:        typeString("", pkgName, param.Type),
-					})
+				for j := 0; j < structType.NumFields(); j++ {
+					variation.ReturnedFields = append(variation.ReturnedFields, convertField(qualifier, i, structType.Field(j)))
 				}
 				continue
 			}
 
 			// local struct
-			if isLocalType(true, ret.Type) {
-				localType, ok := sourceImpl.GetType(ret.Type)
-				if !ok {
-					log.Fatalf("local type not found: %s", typeString(pkgName, "", ret.Type))
-				}
-				if structType, ok := localType.Type.(*ast.StructType); ok {
-					if !hasUnexportedField(structType.Fields.List) {
+			if sourceImpl.IsLocal(ret.Type()) {
+				if structType, ok := derefType(ret.Type()).Underlying().(*types.Struct); ok {
+					if !hasUnexportedField(structType) {
 						// local struct with exported fields
 						variation.MergeReturnedStruct = true
-						for _, param := range structType.Fields.List {
-							variation.ReturnedFields = append(variation.ReturnedFields, &Field{
-								SourceIndex: i,
-								Name:        param.Names[0].Name,
-								Type:        typeString("", pkgName, param.Type),
-							})
+						for j := 0; j < structType.NumFields(); j++ {
+							variation.ReturnedFields = append(variation.ReturnedFields, convertField(qualifier, i, structType.Field(j)))
 						}
 					} else {
 						// local struct with unexported fields
 						variation.ReturnedFields = append(variation.ReturnedFields, &Field{
 							SourceIndex: i,
 							Name:        pkgNameToMethodPrefix(pkgName) + "Result",
-							Type:        typeString("", pkgName, ret.Type),
+							Type:        types.TypeString(ret.Type(), qualifier),
 						})
 					}
 				}
@@ -331,29 +599,46 @@ func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation)
 
 			// local non-struct or imported
 			if len(variation.ReturnedFields) == 0 {
-				retType := typeString("", pkgName, ret.Type)
-				retNameSuffix := deref(retType)
-				if strings.Contains(retNameSuffix, ".") {
-					retNameSuffix = strings.Title(strings.Join(strings.Split(retNameSuffix, "."), ""))
-				}
 				variation.ReturnedFields = append(variation.ReturnedFields, &Field{
 					SourceIndex: i,
 					Name:        "Value",
-					Type:        retType,
+					Type:        types.TypeString(ret.Type(), qualifier),
 				})
 			}
 
 		}
 	}
 
+	// dispatch to the fallback implementations when the methods are missing
+	if err := addFallbacks(config, allMethods); err != nil {
+		return nil, err
+	}
+
+	// set the dispatch indexes of the variations
+	for _, method := range allMethods {
+		for _, variation := range method.Variations {
+			variation.TagIndex = findSourceIndex(config.Sources, variation.Tag)
+		}
+	}
+
+	// shape the methods after the target interface
+	if target != nil {
+		if err := implementInterface(config, sourceImpls, target, allMethods, imports.Qualifier); err != nil {
+			return nil, err
+		}
+	}
+
 	// construct all bucket method inputs and outputs
 	for _, method := range allMethods {
+		if method.Implements {
+			continue
+		}
 		for _, variation := range method.Variations {
 			// merge args
-			method.Args = mergeFields(variation.Args, method.Args)
+			method.Args = mergeFields(alt, variation.Args, method.Args)
 
 			// merge return fields
-			method.ReturnType.Fields = mergeFields(variation.ReturnedFields, method.ReturnType.Fields)
+			method.ReturnType.Fields = mergeFields(alt, variation.ReturnedFields, method.ReturnType.Fields)
 		}
 
 		// decide on return value
@@ -363,37 +648,18 @@ func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation)
 		case 1:
 			method.SingleReturn = true
 			method.ReturnType.Name = method.ReturnType.Fields[0].Type
+		default:
+			method.MultiReturn = isMultiReturn(method)
 		}
 	}
 
-	// merge all imports for inputs and outputs
-	for _, method := range allMethods {
-		for _, field := range method.Args {
-			impl := sourceImpls[field.SourceIndex]
-			imp, ok := impl.GetImport(field.Type)
-			if ok {
-				config.Output.Imports = mergeImport(imp, config.Output.Imports)
-			}
-		}
-		for _, field := range method.ReturnType.Fields {
-			impl := sourceImpls[field.SourceIndex]
-			imp, ok := impl.GetImport(field.Type)
-			if ok {
-				config.Output.Imports = mergeImport(imp, config.Output.Imports)
-			}
-		}
-	}
-
-	// merge init arg imports
-	for _, field := range config.Output.InitArgs {
-		impl := sourceImpls[field.SourceIndex]
-		imp, ok := impl.GetImport(field.Type)
-		if ok {
-			config.Output.Imports = mergeImport(imp, config.Output.Imports)
-		}
+	// keep the methods without an error if configured
+	if err := setNoErrorPolicy(config, allMethods); err != nil {
+		return nil, err
 	}
 
-	// set all methods in the config
+	// set all imports and methods in the config
+	config.Output.Imports = imports.List()
 	config.Output.Methods = allMethods
 
 	// rewrite some names: constructor (init) args, method names, method inputs, method outputs
@@ -403,6 +669,10 @@ func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation)
 		initArg.Type = rewriter.Rewrite(initArg.Type)
 	}
 	for _, method := range config.Output.Methods {
+		// the interface methods are exactly as declared
+		if method.Implements {
+			continue
+		}
 		method.Name = rewriter.Rewrite(method.Name)
 		for _, arg := range method.Args {
 			arg.Name = rewriter.Rewrite(arg.Name)
@@ -415,155 +685,359 @@ func mergeAndGenerate(config *MergeConfig, sourceImpls []*SourceImplementation)
 		}
 	}
 
+	// variadic params can only be declared as variadic at the end
+	setEllipsis(config.Output.InitArgs)
+	for _, method := range config.Output.Methods {
+		setEllipsis(method.Args)
+	}
+
+	// list the methods which each tag supports
+	for _, source := range config.Sources {
+		tagMethods := &TagMethods{Tag: source.Tag}
+		for _, method := range config.Output.Methods {
+			for _, variation := range method.Variations {
+				if variation.Tag == source.Tag {
+					tagMethods.Methods = append(tagMethods.Methods, method.Name)
+					break
+				}
+			}
+		}
+		config.Output.TagMethods = append(config.Output.TagMethods, tagMethods)
+	}
+
+	// generate the interface and the fake in separate files if specified
+	if len(config.Output.Interface.Name) > 0 && len(config.Output.Interface.File) > 0 {
+		if err := generateExtraFile(config, interfaceFileTemplate, config.Output.Interface.File); err != nil {
+			return nil, err
+		}
+	}
+	if len(config.Output.Fake.Name) > 0 && len(config.Output.Fake.File) > 0 {
+		if err := generateExtraFile(config, fakeFileTemplate, config.Output.Fake.File); err != nil {
+			return nil, err
+		}
+	}
+
 	// finally, execute the config on the template and return
+	code, err := executeTemplate(codeTemplate, config)
+	if err != nil {
+		return nil, err
+	}
+	return formatCode(config.Output.File, code)
+}
+
+func generateExtraFile(config *MergeConfig, text, filePath string) error {
+	b, err := executeTemplate(text, config)
+	if err != nil {
+		return err
+	}
+	if b, err = formatCode(filePath, b); err != nil {
+		return err
+	}
+	config.Output.ExtraFiles = append(config.Output.ExtraFiles, &File{
+		Path: filePath,
+		Code: b,
+	})
+	return nil
+}
+
+var templateFuncs = template.FuncMap{
+	"dict": func(pairs ...interface{}) map[string]interface{} {
+		m := make(map[string]interface{})
+		for i := 0; i+1 < len(pairs); i += 2 {
+			m[pairs[i].(string)] = pairs[i+1]
+		}
+		return m
+	},
+	"export": func(name string) string {
+		return strings.ToUpper(name[:1]) + name[1:]
+	},
+}
+
+func executeTemplate(text string, config *MergeConfig) ([]byte, error) {
 	buffer := new(bytes.Buffer)
-	tmpl := template.Must(template.New("").Parse(codeTemplate))
+	tmpl := template.Must(template.New("").Funcs(templateFuncs).Parse(text))
+	template.Must(tmpl.Parse(signatureTemplate))
+	template.Must(tmpl.Parse(dispatchTemplate))
+	template.Must(tmpl.Parse(interfaceTemplate))
+	template.Must(tmpl.Parse(fakeTemplate))
+	template.Must(tmpl.Parse(viewTemplate))
 	if err := tmpl.Execute(buffer, config); err != nil {
 		return nil, err
 	}
 	return []byte(strings.TrimSpace(string(buffer.Bytes()))), nil
 }
 
-var altParamIndex int = 0
+var (
+	blankLinesAfterOpen   = regexp.MustCompile(`([{(])\n(?:[ \t]*\n)+`)
+	blankLinesBeforeClose = regexp.MustCompile(`\n(?:[ \t]*\n)+([ \t]*[})])`)
+)
 
-func getAltSuffix() string {
-	altParamIndex++
-	return fmt.Sprintf("Alt%d", altParamIndex)
+// formatCode formats the code like goimports: the unused imports are removed and the rest
+// are sorted and grouped. Unlike goimports, no missing imports are added from the environment
+// so the output depends only on the sources. The blank lines which the templates leave at the
+// edges of the blocks are dropped as well.
+func formatCode(filename string, code []byte) ([]byte, error) {
+	code = blankLinesAfterOpen.ReplaceAll(code, []byte("$1\n"))
+	code = blankLinesBeforeClose.ReplaceAll(code, []byte("\n$1"))
+	code, err := fixImports(filename, code)
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(code)
 }
 
-func isNewParam(pkgName string, sourceIndex int, param *ast.Field, knownParams []*Field) (*Field, bool) {
-	foundParam := convertField(pkgName, sourceIndex, param)
-	for _, knownParam := range knownParams {
-		if foundParam.Name == knownParam.Name && foundParam.Type == knownParam.Type {
-			return foundParam, false
+// fixImports rewrites the import declaration with the used imports only, the standard
+// library imports grouped before the others.
+func fixImports(filename string, code []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, code, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	// the generated code refers to the packages only with the selectors
+	used := make(map[string]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var stdImports, otherImports []string
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		// the generated imports are named unless the name is the last path element
+		name, line := path.Base(importPath), spec.Path.Value
+		if spec.Name != nil {
+			name, line = spec.Name.Name, spec.Name.Name+" "+spec.Path.Value
+		}
+		if !used[name] {
+			continue
+		}
+		if firstElem, _, _ := strings.Cut(importPath, "/"); strings.Contains(firstElem, ".") {
+			otherImports = append(otherImports, line)
+		} else {
+			stdImports = append(stdImports, line)
 		}
 	}
+
+	var decls []ast.Decl
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			decls = append(decls, genDecl)
+		}
+	}
+	if len(decls) == 0 {
+		return code, nil
+	}
+
+	var b bytes.Buffer
+	if len(stdImports)+len(otherImports) > 0 {
+		b.WriteString("import (\n")
+		for _, line := range stdImports {
+			b.WriteString("\t" + line + "\n")
+		}
+		if len(stdImports) > 0 && len(otherImports) > 0 {
+			b.WriteString("\n")
+		}
+		for _, line := range otherImports {
+			b.WriteString("\t" + line + "\n")
+		}
+		b.WriteString(")")
+	}
+
+	// replace all of the import declarations with the new one
+	var fixed []byte
+	start := fset.Position(decls[0].Pos()).Offset
+	fixed = append(fixed, code[:start]...)
+	fixed = append(fixed, b.Bytes()...)
+	end := start
+	for _, decl := range decls {
+		fixed = append(fixed, code[end:fset.Position(decl.Pos()).Offset]...)
+		end = fset.Position(decl.End()).Offset
+	}
+	return append(fixed, code[end:]...), nil
+}
+
+// altSuffixes numbers the names of the params which collide with a known param of a different
+// type. It is kept per generation so that the output does not depend on what was generated before.
+type altSuffixes struct {
+	index int
+}
+
+func (alt *altSuffixes) next() string {
+	alt.index++
+	return fmt.Sprintf("Alt%d", alt.index)
+}
+
+func isNewParam(alt *altSuffixes, foundParam *Field, knownParams []*Field) (*Field, bool) {
+	if knownParam := findSameField(foundParam, knownParams); knownParam != nil {
+		foundParam.Name = knownParam.Name
+		return foundParam, false
+	}
 	// if there is a known param that is of a different type, use alt name but include
 	for _, knownParam := range knownParams {
 		if foundParam.Name == knownParam.Name && foundParam.Type != knownParam.Type {
-			foundParam.Name += getAltSuffix()
+			foundParam.Name += alt.next()
 			return foundParam, true
 		}
 	}
 	return foundParam, true
 }
 
-func convertField(pkgName string, sourceIndex int, astField *ast.Field) *Field {
-	var field Field
-	field.Name = astField.Names[0].Name
-	field.SourceIndex = sourceIndex
-
-	typ := typeString("", pkgName, astField.Type)
-	if len(typ) == 0 {
-		panic(fmt.Sprintf("unhandled field type %s", reflect.TypeOf(astField.Type)))
+func convertField(qualifier types.Qualifier, sourceIndex int, v *types.Var) *Field {
+	return &Field{
+		SourceIndex: sourceIndex,
+		Name:        v.Name(),
+		Type:        types.TypeString(v.Type(), qualifier),
 	}
-	field.Type = typ
-
-	return &field
 }
 
-func typeString(name string, pkgName string, expr ast.Expr) string {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		ident := t.String()
-		// resolve exported local types in the package
-		if (name == "" || name == "*") && strings.ToUpper(string(ident[0])) == string(ident[0]) && len(pkgName) > 0 {
-			return name + pkgName + "." + t.Name
-		}
-		return name + t.Name
-
-	case *ast.StarExpr:
-		return typeString("*", pkgName, t.X)
+func valueName(j int) string {
+	if j == 0 {
+		return "val"
+	}
+	return fmt.Sprintf("val%d", j)
+}
 
-	case *ast.SelectorExpr:
-		return name + typeString("", pkgName, t.X) + "." + t.Sel.Name
+// resultName returns the output field name of the j'th result.
+func resultName(results *types.Tuple, j int) string {
+	name := results.At(j).Name()
+	if len(name) > 0 && name != "_" {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	if j == 0 {
+		return "Value"
+	}
+	return fmt.Sprintf("Value%d", j)
+}
 
-	case *ast.ArrayType:
-		ret := name + "["
-		if t.Len != nil {
-			ret += types.ExprString(t.Len)
+func findUnexportedResult(results *types.Tuple) (*types.Var, bool) {
+	for j := 0; j < results.Len(); j++ {
+		named, ok := derefType(results.At(j).Type()).(*types.Named)
+		if ok && named.Obj().Pkg() != nil && !named.Obj().Exported() {
+			return results.At(j), true
 		}
-		return ret + "]" + typeString("", pkgName, t.Elt)
+	}
+	return nil, false
+}
 
-	case *ast.ChanType:
-		chanStr := "chan"
-		switch t.Dir {
-		case ast.SEND:
-			chanStr += "<-"
-		case ast.RECV:
-			chanStr = "<-" + chanStr
+// isMultiReturn tells if the multiple values of the method can be returned as they are:
+// all variations should return the same values.
+func isMultiReturn(method *Method) bool {
+	for _, variation := range method.Variations {
+		if !variation.MultiReturn || len(variation.ReturnedFields) != len(method.ReturnType.Fields) {
+			return false
 		}
-		return name + chanStr + " " + typeString("", pkgName, t.Value)
-
-	case *ast.MapType:
-		return fmt.Sprintf("map[%s]%s", typeString("", pkgName, t.Key), typeString("", pkgName, t.Value))
+	}
+	return true
+}
 
-	default:
-		return name + types.ExprString(expr)
+// convertParams converts the params of a signature to fields. Unnamed and blank params
+// get synthesized names so that they can be forwarded.
+func convertParams(qualifier types.Qualifier, sourceIndex int, signature *types.Signature) []*Field {
+	params := signature.Params()
+	var fields []*Field
+	for j := 0; j < params.Len(); j++ {
+		field := convertField(qualifier, sourceIndex, params.At(j))
+		field.Name = paramName(params, j)
+		field.Variadic = signature.Variadic() && j == params.Len()-1
+		fields = append(fields, field)
 	}
+	return fields
 }
 
-func deref(name string) string {
-	if name[0] == '*' {
-		return name[1:]
+func paramName(params *types.Tuple, j int) string {
+	name := params.At(j).Name()
+	if len(name) > 0 && name != "_" {
+		return name
+	}
+	name = fmt.Sprintf("arg%d", j)
+	for hasParam(params, name) {
+		name += "_"
 	}
 	return name
 }
 
-func isLocalType(is bool, expr ast.Expr) bool {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		// if first char is upper case
-		if strings.ToUpper(string(t.Name[0])) == string(t.Name[0]) {
-			return true && is
+func hasParam(params *types.Tuple, name string) bool {
+	for j := 0; j < params.Len(); j++ {
+		if params.At(j).Name() == name {
+			return true
 		}
-		return false
-
-	case *ast.StarExpr:
-		return isLocalType(true && is, t.X)
+	}
+	return false
+}
 
-	case *ast.SelectorExpr:
-		return false
+// setEllipsis makes the last arg variadic in the declaration if it is forwarded as variadic.
+func setEllipsis(args []*Field) {
+	if len(args) > 0 && args[len(args)-1].Variadic {
+		args[len(args)-1].Ellipsis = true
+	}
+}
 
-	default:
-		return false
+func derefType(typ types.Type) types.Type {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		return ptr.Elem()
 	}
+	return typ
 }
 
-func mergeFields(from, to []*Field) []*Field {
+func isErrorType(typ types.Type) bool {
+	return types.Identical(typ, types.Universe.Lookup("error").Type())
+}
+
+func mergeFields(alt *altSuffixes, from, to []*Field) []*Field {
 	for _, fromField := range from {
-		var exists bool
+		if toField := findSameField(fromField, to); toField != nil {
+			fromField.Name = toField.Name
+			continue
+		}
 		for _, toField := range to {
-			if fromField.Name == toField.Name && fromField.Type == toField.Type {
-				exists = true
-				break
-			}
-			if fromField.Name == toField.Name && fromField.Type != toField.Type {
-				fromField.Name += getAltSuffix()
+			if fromField.Name == toField.Name {
+				fromField.Name += alt.next()
 				break
 			}
 		}
-		if !exists {
-			to = append(to, fromField)
-		}
+		to = append(to, fromField)
 	}
 	return to
 }
 
-func mergeImport(imp string, imports []string) []string {
-	for _, oldImp := range imports {
-		if imp == oldImp {
-			return imports
+// findSameField finds the known field with the same name and type. The fields which were
+// renamed with an alt suffix for an earlier name clash are matched by the original name.
+func findSameField(field *Field, known []*Field) *Field {
+	for _, knownField := range known {
+		if knownField.Name == field.Name && knownField.Type == field.Type {
+			return knownField
 		}
 	}
-	imports = append(imports, imp)
-	return imports
+	for _, knownField := range known {
+		if knownField.Type == field.Type && isAltName(knownField.Name, field.Name) {
+			return knownField
+		}
+	}
+	return nil
 }
 
-func hasUnexportedField(fields []*ast.Field) bool {
-	for _, field := range fields {
-		firstLetter := string(field.Names[0].Name[0])
-		if strings.ToLower(firstLetter) == string(firstLetter) {
+// isAltName tells if the name is the base name with an alt suffix.
+func isAltName(name, base string) bool {
+	suffix, ok := strings.CutPrefix(name, base+"Alt")
+	if !ok || len(suffix) == 0 {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+func hasUnexportedField(structType *types.Struct) bool {
+	for i := 0; i < structType.NumFields(); i++ {
+		if !structType.Field(i).Exported() {
 			return true
 		}
 	}
@@ -577,3 +1051,71 @@ func pkgNameToMethodPrefix(pkgName string) string {
 	}
 	return strings.Join(parts, "")
 }
+
+// importSet keeps track of the packages which the output refers to and makes sure
+// that each package is imported with a unique name.
+type importSet struct {
+	names   map[string]string // import path -> name
+	paths   map[string]string // name -> import path
+	imports []string
+	local   string // import path of the output package, if known
+}
+
+func newImportSet(sources []*Source) *importSet {
+	set := &importSet{
+		names: make(map[string]string),
+		paths: map[string]string{
+			// reserved by the template
+			"import_errors":  "errors",
+			"import_fmt":     "fmt",
+			"import_strconv": "strconv",
+			"import_strings": "strings",
+			"import_sync":    "sync",
+			"import_atomic":  "sync/atomic",
+		},
+	}
+	// the reserved imports are referred with their names as well
+	for name, importPath := range set.paths {
+		set.names[importPath] = name
+	}
+	// source packages are already imported by the template
+	for _, source := range sources {
+		if _, ok := set.names[source.Package.ImportPath]; !ok {
+			set.names[source.Package.ImportPath] = source.Package.Alias
+		}
+		set.paths[source.Package.Alias] = source.Package.ImportPath
+	}
+	return set
+}
+
+// Qualifier refers to the packages from the output code. The first alias is used for
+// the source packages which are listed multiple times.
+func (set *importSet) Qualifier(pkg *types.Package) string {
+	return set.add(pkg)
+}
+
+func (set *importSet) add(pkg *types.Package) string {
+	if pkg.Path() == set.local {
+		return ""
+	}
+	if name, ok := set.names[pkg.Path()]; ok {
+		return name
+	}
+	name := pkg.Name()
+	for i := 2; len(set.paths[name]) > 0; i++ {
+		name = fmt.Sprintf("%s%d", pkg.Name(), i)
+	}
+	set.names[pkg.Path()] = name
+	set.paths[name] = pkg.Path()
+	if name == path.Base(pkg.Path()) {
+		set.imports = append(set.imports, strconv.Quote(pkg.Path()))
+	} else {
+		set.imports = append(set.imports, fmt.Sprintf("%s %s", name, strconv.Quote(pkg.Path())))
+	}
+	return name
+}
+
+// List returns the import specs in the order of appearance.
+func (set *importSet) List() []string {
+	return set.imports
+}
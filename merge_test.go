@@ -1,9 +1,21 @@
 package merge
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 
+	"github.com/forta-network/go-merge-types/rewrite"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,3 +30,928 @@ func TestMerge(t *testing.T) {
 	r.NotNil(config)
 	r.Equal(string(expectedOut), string(b))
 }
+
+func TestMergeAtomic(t *testing.T) {
+	r := require.New(t)
+
+	expectedOut, err := os.ReadFile("_testdata/expected_atomic.go")
+	r.NoError(err)
+
+	config, b, err := Run("example/example-atomic-gomergetypes.yml")
+	r.NoError(err)
+	r.NotNil(config)
+	r.Equal(string(expectedOut), string(b))
+}
+
+func TestMergeIndexDispatch(t *testing.T) {
+	r := require.New(t)
+
+	expectedOut, err := os.ReadFile("_testdata/expected_index.go")
+	r.NoError(err)
+
+	targets, err := RunAll("example/example-many-gomergetypes.yml")
+	r.NoError(err)
+	r.Len(targets, 2)
+	r.True(targets[1].Config.Output.IndexDispatch)
+	r.Equal(string(expectedOut), string(targets[1].Code))
+}
+
+func TestMergeFromImportPaths(t *testing.T) {
+	r := require.New(t)
+
+	expectedOut, err := os.ReadFile("_testdata/expected.go")
+	r.NoError(err)
+
+	config, b, err := Run("_testdata/import-path-gomergetypes.yml")
+	r.NoError(err)
+	r.NotNil(config)
+	r.Equal(string(expectedOut), string(b))
+}
+
+func TestMergeMultipleTargets(t *testing.T) {
+	r := require.New(t)
+
+	expectedOut, err := os.ReadFile("_testdata/expected.go")
+	r.NoError(err)
+
+	targets, err := RunAll("_testdata/multi-gomergetypes.yml")
+	r.NoError(err)
+	r.Len(targets, 2)
+	r.Equal(string(expectedOut), string(targets[0].Code))
+	r.Equal("Impl13", targets[1].Config.Output.Type)
+	r.Equal("v0.0.1", targets[1].Config.Output.DefaultTag)
+	r.Contains(string(targets[1].Code), "typ1    *pkg3_2.Impl3")
+	// the errors are declared once in the output package
+	r.Contains(string(targets[0].Code), "var ErrNotImplemented")
+	r.NotContains(string(targets[1].Code), "var ErrNotImplemented")
+	r.Contains(string(targets[1].Code), "&NotImplementedError{")
+}
+
+func TestMergeSharedPackage(t *testing.T) {
+	r := require.New(t)
+
+	// separate configs generate into the same package: the errors are declared in a.go
+	for _, name := range []string{"a", "b"} {
+		expectedOut, err := os.ReadFile(fmt.Sprintf("_testdata/sharedpkg/%s.go", name))
+		r.NoError(err)
+		targets, err := RunAll(fmt.Sprintf("_testdata/shared-%s-gomergetypes.yml", name))
+		r.NoError(err)
+		r.Equal(string(expectedOut), string(targets[0].Code))
+	}
+
+	a, err := os.ReadFile("_testdata/sharedpkg/a.go")
+	r.NoError(err)
+	b, err := os.ReadFile("_testdata/sharedpkg/b.go")
+	r.NoError(err)
+	r.Contains(string(a), "var ErrNotImplemented")
+	r.NotContains(string(b), "var ErrNotImplemented")
+	r.Contains(string(b), "&NotImplementedError{")
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	r := require.New(t)
+
+	expectedOut, err := os.ReadFile("_testdata/expected_index.go")
+	r.NoError(err)
+
+	// the same config can be generated again
+	configs, err := ReadConfig("example/example-many-gomergetypes.yml")
+	r.NoError(err)
+	for i := 0; i < 2; i++ {
+		b, err := Generate(configs[1])
+		r.NoError(err)
+		r.Equal(string(expectedOut), string(b))
+	}
+
+	// concurrent generations do not share any state
+	var (
+		wg      sync.WaitGroup
+		targets = make([][]*Target, 4)
+		errs    = make([]error, len(targets))
+	)
+	for i := range targets {
+		wg.Go(func() {
+			targets[i], errs[i] = RunAll("example/example-many-gomergetypes.yml")
+		})
+	}
+	wg.Wait()
+	for i := range targets {
+		r.NoError(errs[i])
+		r.Len(targets[i], 2)
+		r.Equal(string(targets[0][0].Code), string(targets[i][0].Code))
+		r.Equal(string(expectedOut), string(targets[i][1].Code))
+	}
+}
+
+func TestTargetDiff(t *testing.T) {
+	r := require.New(t)
+
+	targets, err := RunAll("example/example-gomergetypes.yml")
+	r.NoError(err)
+	r.Len(targets, 1)
+	target := targets[0]
+
+	// the example output is up to date
+	diff, err := target.Diff()
+	r.NoError(err)
+	r.Empty(diff)
+
+	target.Code = bytes.Replace(target.Code, []byte("type Impl struct"), []byte("type Impl2 struct"), 1)
+	diff, err = target.Diff()
+	r.NoError(err)
+	r.Contains(diff, "--- example/outpkg/out.go\n+++ example/outpkg/out.go (generated)\n")
+	r.Contains(diff, "\n-type Impl struct {\n+type Impl2 struct {\n")
+
+	// missing files are compared as empty
+	target.Config.Output.File = "./outpkg/missing.go"
+	diff, err = target.Diff()
+	r.NoError(err)
+	r.Contains(diff, "+// Code generated by go-merge-types. DO NOT EDIT.\n")
+}
+
+func TestMergeSingleTarget(t *testing.T) {
+	r := require.New(t)
+
+	_, _, err := Run("_testdata/multi-gomergetypes.yml")
+	r.Error(err)
+}
+
+func TestLoaderReuse(t *testing.T) {
+	r := require.New(t)
+
+	loader := NewLoader()
+	pkg := &Package{ImportPath: "github.com/forta-network/go-merge-types/example/pkg1"}
+	pkg1, err := loader.Load("example", pkg)
+	r.NoError(err)
+	pkg2, err := loader.Load("example", pkg)
+	r.NoError(err)
+	r.Same(pkg1, pkg2)
+}
+
+func TestMergeExtraFiles(t *testing.T) {
+	r := require.New(t)
+
+	configs, err := ReadConfig("example/example-gomergetypes.yml")
+	r.NoError(err)
+	config := configs[0]
+	config.Output.Interface.File = "./outpkg/iface.go"
+	config.Output.Fake.File = "./outpkg/fake.go"
+
+	b, err := Generate(config)
+	r.NoError(err)
+	r.NotContains(string(b), "type ImplInterface interface")
+	r.NotContains(string(b), "type FakeImpl struct")
+
+	r.Len(config.Output.ExtraFiles, 2)
+
+	iface := config.Output.ExtraFiles[0]
+	r.Equal("./outpkg/iface.go", iface.Path)
+	r.Contains(string(iface.Code), "type ImplInterface interface")
+	r.Contains(string(iface.Code), "var _ ImplInterface = &Impl{}")
+	// only the imports used by the methods are kept
+	r.Contains(string(iface.Code), `"math/big"`)
+	r.NotContains(string(iface.Code), `"sync"`)
+	r.NotContains(string(iface.Code), "pkg1")
+
+	fake := config.Output.ExtraFiles[1]
+	r.Equal("./outpkg/fake.go", fake.Path)
+	r.Contains(string(fake.Code), "type FakeImpl struct")
+	r.Contains(string(fake.Code), "var _ ImplInterface = &FakeImpl{}")
+	r.Contains(string(fake.Code), `import_sync "sync"`)
+}
+
+func TestMergeFormatted(t *testing.T) {
+	r := require.New(t)
+
+	configs, err := ReadConfig("example/example-gomergetypes.yml")
+	r.NoError(err)
+	config := configs[0]
+	config.Output.Interface.File = "./outpkg/iface.go"
+	config.Output.Fake.File = "./outpkg/fake.go"
+
+	b, err := Generate(config)
+	r.NoError(err)
+
+	files := [][]byte{b}
+	for _, file := range config.Output.ExtraFiles {
+		files = append(files, file.Code)
+	}
+	for _, code := range files {
+		formatted, err := format.Source(code)
+		r.NoError(err)
+		r.Equal(string(formatted), string(code))
+		r.NotContains(string(code), "{\n\n")
+		r.NotContains(string(code), "\n\n}")
+
+		file, err := parser.ParseFile(token.NewFileSet(), "", code, 0)
+		r.NoError(err)
+		// each package is imported once
+		importPaths := make(map[string]bool)
+		for _, spec := range file.Imports {
+			r.False(importPaths[spec.Path.Value], spec.Path.Value)
+			importPaths[spec.Path.Value] = true
+		}
+		// the functions without results do not end with a redundant return
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Type.Results != nil || len(fn.Body.List) == 0 {
+				continue
+			}
+			_, ok = fn.Body.List[len(fn.Body.List)-1].(*ast.ReturnStmt)
+			r.False(ok, fn.Name.Name)
+		}
+	}
+	// the standard library imports are grouped before the others
+	r.Contains(string(b), "import_atomic \"sync/atomic\"\n\n\tpkg1 ")
+}
+
+func TestFormatCode(t *testing.T) {
+	r := require.New(t)
+
+	code := []byte(`package outpkg
+
+import (
+	pkg1 "github.com/forta-network/go-merge-types/example/pkg1"
+	import_fmt "fmt"
+	"math/big"
+	"sync"
+)
+
+func f() (*big.Int, *pkg1.Impl1) {
+
+	return nil, strings.ToUpper(import_fmt.Sprint())
+}
+`)
+	b, err := formatCode("out.go", code)
+	r.NoError(err)
+	// the unused imports are removed and the missing ones are not added
+	r.Equal(`package outpkg
+
+import (
+	import_fmt "fmt"
+	"math/big"
+
+	pkg1 "github.com/forta-network/go-merge-types/example/pkg1"
+)
+
+func f() (*big.Int, *pkg1.Impl1) {
+	return nil, strings.ToUpper(import_fmt.Sprint())
+}
+`, string(b))
+}
+
+func TestMergeParams(t *testing.T) {
+	r := require.New(t)
+
+	configs, err := ReadConfig("example/example-atomic-gomergetypes.yml")
+	r.NoError(err)
+	config := configs[0]
+	config.Output.Interface = Interface{Name: "ImplInterface"}
+	config.Output.Fake = Fake{Name: "FakeImpl"}
+
+	b, err := Generate(config)
+	r.NoError(err)
+	code := string(b)
+
+	// variadic constructor param
+	r.Contains(code, "opts ...string) (*Impl, error)")
+	r.Contains(code, "pkg4.NewImpl4[*big.Int](arg2, opts...)")
+	// grouped params
+	r.Contains(code, "Sum(a int, b int) (retVal int, err error)")
+	r.Contains(code, "merged.typ3.Sum(a, b)")
+	// unnamed params
+	r.Contains(code, "Store(arg0 *big.Int, arg1 string) (err error)")
+	r.Contains(code, "merged.typ3.Store(arg0, arg1)")
+	// variadic params
+	r.Contains(code, "Append(prefix string, vals ...*big.Int) (err error)")
+	r.Contains(code, "merged.typ3.Append(prefix, vals...)")
+	r.Contains(code, "return fn(prefix, vals...)")
+	// the renamed fields are reused by the later sources
+	r.Contains(code, "\tValueAlt3 *big.Int\n}")
+	r.Contains(code, "retVal.ValueAlt3 = val\n")
+}
+
+func TestMergeRepeatedSources(t *testing.T) {
+	r := require.New(t)
+
+	// the same packages are merged four times: each source argument is merged once
+	configs, err := ReadConfig("example/example-many-gomergetypes.yml")
+	r.NoError(err)
+	for _, config := range configs {
+		_, err := Generate(config)
+		r.NoError(err)
+		r.Len(config.Output.InitArgs, 5)
+		foo := findMethod(config.Output.Methods, "Foo")
+		r.Len(foo.Args, 4)
+		r.Equal("arg3Alt2", foo.Args[3].Name)
+	}
+}
+
+func TestMergeMultipleValues(t *testing.T) {
+	r := require.New(t)
+
+	config, _, err := Run("example/example-gomergetypes.yml")
+	r.NoError(err)
+	lookup := findMethod(config.Output.Methods, "Lookup")
+	r.NotNil(lookup)
+	// the variations agree on the values
+	r.True(lookup.MultiReturn)
+	r.Len(lookup.ReturnType.Fields, 2)
+
+	config, _, err = Run("example/example-atomic-gomergetypes.yml")
+	r.NoError(err)
+	lookup = findMethod(config.Output.Methods, "Lookup")
+	r.NotNil(lookup)
+	// the values are merged into a struct
+	r.False(lookup.MultiReturn)
+	r.Len(lookup.ReturnType.Fields, 3)
+}
+
+func TestMergeNoError(t *testing.T) {
+	r := require.New(t)
+
+	configs, err := ReadConfig("example/example-gomergetypes.yml")
+	r.NoError(err)
+	config := configs[0]
+	config.Output.NoError.Policy = NoErrorPolicyPanic
+
+	b, err := Generate(config)
+	r.NoError(err)
+	code := string(b)
+
+	r.Contains(code, "func (merged *Impl) Lookup(key string) (retVal int, retVal1 bool) {")
+	r.Contains(code, "func (merged *Impl) FooBarBaz() {")
+	r.Contains(code, `panic(&NotImplementedError{Type: "Impl", Method: "Lookup", Tag: merged.currTag})`)
+	r.NotContains(code, "LastError")
+	// methods returning an error are not affected
+	r.Contains(code, "func (merged *Impl) SingleReturnVal(arg string) (retVal int, err error) {")
+
+	configs, err = ReadConfig("example/example-gomergetypes.yml")
+	r.NoError(err)
+	config = configs[0]
+	config.Output.NoError.Policy = "ignore"
+
+	_, err = Generate(config)
+	r.ErrorIs(err, ErrInvalidNoError)
+}
+
+func TestMergeImplements(t *testing.T) {
+	r := require.New(t)
+
+	expectedOut, err := os.ReadFile("_testdata/expected_api.go")
+	r.NoError(err)
+
+	config, b, err := Run("example/example-api-gomergetypes.yml")
+	r.NoError(err)
+	r.Equal("api.Impl", config.Output.Implements.TypeName)
+	r.Equal(string(expectedOut), string(b))
+
+	foo := findMethod(config.Output.Methods, "Foo")
+	r.True(foo.Implements)
+	r.Len(foo.Variations, 1)
+	r.Equal("v0.0.3", foo.Variations[0].Tag)
+
+	// the dropped variations of v0.0.1 and v0.0.2 are reported
+	var dropped []*SourceError
+	for _, warning := range config.Warnings {
+		var srcErr *SourceError
+		if errors.As(warning, &srcErr) && errors.Is(srcErr, ErrIncompatibleVariation) {
+			dropped = append(dropped, srcErr)
+		}
+	}
+	r.Len(dropped, 2)
+	for i, srcErr := range dropped {
+		r.Equal(i, srcErr.SourceIndex)
+		r.Equal("Foo", srcErr.Name)
+		r.Equal("method.go", filepath.Base(srcErr.Position.Filename))
+	}
+	r.Contains(dropped[0].Error(), "returns (*pkg1.Result1) instead of (*big.Int)")
+	r.Contains(dropped[1].Error(), "returns (*pkg2.Int) instead of (*big.Int)")
+}
+
+func TestMergeImplementsErrors(t *testing.T) {
+	r := require.New(t)
+
+	configs, err := ReadConfig("example/example-api-gomergetypes.yml")
+	r.NoError(err)
+	config := configs[0]
+	config.Output.Implements.Name = "Extended"
+
+	_, err = Generate(config)
+	r.ErrorIs(err, ErrInterfaceNotSatisfied)
+	r.Contains(err.Error(), "example/api.Extended")
+	r.Contains(err.Error(), "Baz: no source has the method")
+	r.Contains(err.Error(), "Bar: no source has a compatible variation of func(arg1 chan *string) (int, error)")
+
+	configs, err = ReadConfig("example/example-api-gomergetypes.yml")
+	r.NoError(err)
+	config = configs[0]
+	config.Output.NoError.Policy = NoErrorPolicyNone
+
+	_, err = Generate(config)
+	r.ErrorIs(err, ErrInterfaceNotSatisfied)
+	r.Contains(err.Error(), "Lookup: does not return an error")
+
+	configs, err = ReadConfig("example/example-api-gomergetypes.yml")
+	r.NoError(err)
+	config = configs[0]
+	config.Output.Implements.Name = "Missing"
+
+	_, err = Generate(config)
+	r.ErrorIs(err, ErrInterfaceNotFound)
+}
+
+func TestMergeWarnings(t *testing.T) {
+	r := require.New(t)
+
+	config, _, err := Run("example/example-gomergetypes.yml")
+	r.NoError(err)
+	r.Len(config.Warnings, 1)
+
+	var srcErr *SourceError
+	r.ErrorAs(config.Warnings[0], &srcErr)
+	r.ErrorIs(srcErr, ErrUnsupportedReturn)
+	r.Equal(1, srcErr.SourceIndex)
+	r.Equal("Cursor", srcErr.Name)
+	r.Equal("method.go", filepath.Base(srcErr.Position.Filename))
+}
+
+func TestReadConfigErrors(t *testing.T) {
+	r := require.New(t)
+
+	_, err := ReadConfig("_testdata/invalid-gomergetypes.yml")
+	r.Error(err)
+
+	var cfgErrs []*ConfigError
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var cfgErr *ConfigError
+		r.ErrorAs(err, &cfgErr)
+		cfgErrs = append(cfgErrs, cfgErr)
+	}
+
+	expected := []struct {
+		line, column int
+		err          error
+		msg          string
+	}{
+		{8, 7, ErrUnknownConfigField, "improtPath"},
+		{6, 5, ErrMissingConfigField, "type"},
+		{6, 10, ErrDuplicateTag, "v0.0.1 is also the tag of source 0"},
+		{8, 7, ErrMissingConfigField, "importPath"},
+		{11, 3, ErrMissingConfigField, "file"},
+		{13, 15, ErrUnknownDefaultTag, "v0.0.3"},
+		{15, 14, ErrInvalidRewrite, "missing closing )"},
+		{17, 14, ErrInvalidRewrite, "0 capture groups"},
+		{24, 16, ErrInvalidRange, "<=0.0.x"},
+		{32, 9, ErrMissingConfigField, "importPath"},
+		{34, 17, ErrInvalidNoError, `unknown policy "ignore"`},
+		{36, 17, ErrInvalidFallback, `unknown policy "newest"`},
+		{38, 25, ErrInvalidFallback, `unknown tag "v0.0.4" for method Foo`},
+		{42, 16, ErrInvalidRange, `">=0.1.0 <0.0.1" matches no version`},
+		{51, 14, ErrInvalidFallback, "nearestOlder policy needs semantic version tags"},
+	}
+	r.Len(cfgErrs, len(expected))
+	for i, exp := range expected {
+		cfgErr := cfgErrs[i]
+		r.Equal("_testdata/invalid-gomergetypes.yml", cfgErr.File)
+		r.Equal(exp.line, cfgErr.Line, cfgErr.Error())
+		r.Equal(exp.column, cfgErr.Column, cfgErr.Error())
+		r.ErrorIs(cfgErr, exp.err)
+		r.Contains(cfgErr.Error(), exp.msg)
+	}
+	r.Contains(err.Error(), "_testdata/invalid-gomergetypes.yml:8:7: unknown config field: improtPath")
+}
+
+func TestParseConfigMissingSources(t *testing.T) {
+	for _, testCase := range []struct {
+		name         string
+		config       string
+		line, column int
+		msg          string
+	}{
+		{
+			name:   "target without sources",
+			config: "targets:\n  - output:\n      type: Empty\n      package: outpkg\n      file: ./outpkg/out.go\n",
+			line:   2,
+			column: 5,
+			msg:    "missing config field: sources",
+		},
+		{
+			name:   "top-level output with targets",
+			config: "output:\n  type: Impl\ntargets:\n  - sources: []\n",
+			line:   2,
+			column: 3,
+			msg:    "missing config field: sources of the top-level output",
+		},
+		{
+			name:   "no targets",
+			config: "output: {}\n",
+			line:   1,
+			column: 9,
+			msg:    "missing config field: sources of the top-level output",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			_, err := parseConfig("gomergetypes.yml", []byte(testCase.config))
+			r.ErrorIs(err, ErrMissingConfigField)
+			var cfgErr *ConfigError
+			r.ErrorAs(err, &cfgErr)
+			r.Equal(testCase.line, cfgErr.Line, cfgErr.Error())
+			r.Equal(testCase.column, cfgErr.Column, cfgErr.Error())
+			r.Contains(cfgErr.Error(), testCase.msg)
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	r := require.New(t)
+
+	// run "make schema" after changing the config types
+	expectedSchema, err := os.ReadFile("gomergetypes.schema.json")
+	r.NoError(err)
+	b, err := Schema()
+	r.NoError(err)
+	r.Equal(string(expectedSchema), string(b))
+
+	var schema jsonSchema
+	r.NoError(json.Unmarshal(b, &schema))
+	for name, typ := range map[string]reflect.Type{
+		"MergeConfig": reflect.TypeOf(MergeConfig{}),
+		"Source":      reflect.TypeOf(Source{}),
+		"Package":     reflect.TypeOf(Package{}),
+		"Output":      reflect.TypeOf(Output{}),
+		"Rule":        reflect.TypeOf(rewrite.Rule{}),
+	} {
+		fields := make(map[string]reflect.Type)
+		collectFields(typ, fields)
+		r.Contains(schema.Defs, name)
+		r.Len(schema.Defs[name].Properties, len(fields), name)
+		for key := range fields {
+			r.Contains(schema.Defs[name].Properties, key, name)
+		}
+	}
+	r.Equal([]string{"type", "tag", "package"}, schema.Defs["Source"].Required)
+	r.Equal(false, schema.Defs["Output"].AdditionalProperties)
+}
+
+func TestScaffold(t *testing.T) {
+	r := require.New(t)
+
+	configPath := "_testdata/contracts-gomergetypes.yml"
+	b, err := Scaffold(configPath, ScaffoldOptions{Dir: "_testdata/contracts", Type: "Contract"})
+	r.NoError(err)
+	r.Equal(`sources:
+  - type: Contract
+    tag: v0.1.0
+    package:
+      importPath: github.com/forta-network/go-merge-types/_testdata/contracts/v0_1_0
+      sourceDir: ./contracts/v0_1_0
+  - type: Contract
+    tag: v0.2.0
+    package:
+      importPath: github.com/forta-network/go-merge-types/_testdata/contracts/v0_2_0
+      sourceDir: ./contracts/v0_2_0
+  - type: Contract
+    tag: v1.0.0
+    package:
+      importPath: github.com/forta-network/go-merge-types/_testdata/contracts/v1_0_0
+      sourceDir: ./contracts/v1_0_0
+
+output:
+  type: Contract
+  package: merged
+  file: ./merged/out.go
+`, string(b))
+
+	// the starter config can be generated as it is
+	file, err := parseConfig(configPath, b)
+	r.NoError(err)
+	prepareConfig(configPath, &file.MergeConfig)
+	code, err := Generate(&file.MergeConfig)
+	r.NoError(err)
+	r.Contains(string(code), "func (merged *Contract) Call(method string) (retVal string, err error)")
+
+	// the single candidate is found without the type
+	b, err = Scaffold(configPath, ScaffoldOptions{
+		Dir:           "_testdata/contracts",
+		TagPattern:    `^v(0)_(\d+)_(\d+)$`,
+		OutputType:    "Merged",
+		OutputPackage: "contracts",
+		OutputFile:    "./contracts/merged.go",
+	})
+	r.NoError(err)
+	r.Contains(string(b), "tag: v0.2.0")
+	r.NotContains(string(b), "tag: v1.0.0")
+	r.Contains(string(b), "output:\n  type: Merged\n  package: contracts\n  file: ./contracts/merged.go\n")
+
+	_, err = Scaffold(configPath, ScaffoldOptions{Dir: "_testdata/contracts"})
+	r.ErrorIs(err, ErrMultipleCandidates)
+	r.ErrorContains(err, "Contract, Filterer")
+
+	_, err = Scaffold(configPath, ScaffoldOptions{Dir: "_testdata/contracts", Type: "Filterer"})
+	r.ErrorIs(err, ErrImplementationNotFound)
+
+	// the captures can include the prefix
+	b, err = Scaffold(configPath, ScaffoldOptions{Dir: "_testdata/contracts", Type: "Contract", TagPattern: `^(v\d+)_(\d+)_(\d+)$`})
+	r.NoError(err)
+	r.Contains(string(b), "tag: v0.1.0")
+
+	// the tags which are not semantic versions are ordered lexically
+	b, err = Scaffold(configPath, ScaffoldOptions{
+		Dir:         "_testdata/contracts",
+		Type:        "Contract",
+		TagPattern:  `^v(\d+)_(\d+)_\d+$`,
+		TagTemplate: "release-$1$2",
+	})
+	r.NoError(err)
+	r.Regexp(`(?s)tag: release-01\n.*tag: release-02\n.*tag: release-10\n`, string(b))
+
+	_, err = Scaffold(configPath, ScaffoldOptions{Dir: "_testdata/contracts", TagPattern: `^v(\d+_\d+_\d+$`})
+	r.ErrorIs(err, ErrInvalidTagPattern)
+
+	_, err = Scaffold(configPath, ScaffoldOptions{Dir: "_testdata/contracts", TagPattern: `^v(\d+)_\d+_\d+$`, TagTemplate: "$2"})
+	r.ErrorIs(err, ErrInvalidTagPattern)
+
+	_, err = Scaffold(configPath, ScaffoldOptions{Dir: "_testdata/contracts", TagPattern: `^release-(.+)$`})
+	r.ErrorIs(err, ErrPackageNotFound)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		source      *Source
+		expectedErr error
+	}{
+		{
+			name: "package not found",
+			source: &Source{
+				Type:    "Impl1",
+				Package: Package{ImportPath: "github.com/forta-network/go-merge-types/example/pkg0"},
+			},
+			expectedErr: ErrPackageNotFound,
+		},
+		{
+			name: "source dir not found",
+			source: &Source{
+				Type:    "Impl1",
+				Package: Package{SourceDir: "example/pkg0"},
+			},
+			expectedErr: ErrPackageNotFound,
+		},
+		{
+			name: "implementation not found",
+			source: &Source{
+				Type:    "Impl2",
+				Package: Package{ImportPath: "github.com/forta-network/go-merge-types/example/pkg1"},
+			},
+			expectedErr: ErrImplementationNotFound,
+		},
+		{
+			name: "constructor not found",
+			source: &Source{
+				Type:    "Result1",
+				Package: Package{ImportPath: "github.com/forta-network/go-merge-types/example/pkg1"},
+			},
+			expectedErr: ErrConstructorNotFound,
+		},
+		{
+			name: "unsupported constructor",
+			source: &Source{
+				Type:    "Config",
+				Package: Package{ImportPath: "github.com/forta-network/go-merge-types/example/pkg4"},
+			},
+			expectedErr: ErrUnsupportedConstructor,
+		},
+		{
+			name: "missing type arguments",
+			source: &Source{
+				Type:    "Impl4",
+				Package: Package{ImportPath: "github.com/forta-network/go-merge-types/example/pkg4"},
+			},
+			expectedErr: ErrInvalidTypeArgs,
+		},
+		{
+			name: "invalid type arguments",
+			source: &Source{
+				Type:     "Impl4",
+				TypeArgs: []string{"*big.Foo"},
+				Package:  Package{ImportPath: "github.com/forta-network/go-merge-types/example/pkg4"},
+			},
+			expectedErr: ErrInvalidTypeArgs,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			_, err := Generate(&MergeConfig{
+				BaseDir: "example",
+				Sources: []*Source{testCase.source},
+			})
+			r.Error(err)
+
+			var srcErr *SourceError
+			r.ErrorAs(err, &srcErr)
+			r.Equal(0, srcErr.SourceIndex)
+			r.NotEmpty(srcErr.Package)
+			if testCase.expectedErr != nil {
+				r.ErrorIs(err, testCase.expectedErr)
+			}
+		})
+	}
+}
+
+func TestGenerateOutputErrors(t *testing.T) {
+	r := require.New(t)
+
+	configs, err := ReadConfig("example/example-atomic-gomergetypes.yml")
+	r.NoError(err)
+	config := configs[0]
+	// break the output with a wrong type
+	config.Output.Rewrite = rewrite.Rewriter{{Match: `^\*big\.(Int)$`, Transform: "*big.Float"}}
+
+	b, err := Generate(config)
+	r.ErrorIs(err, ErrInvalidOutput)
+	r.Nil(b)
+
+	var srcErr *SourceError
+	r.ErrorAs(err, &srcErr)
+	r.Equal(2, srcErr.SourceIndex)
+	r.Equal("Foo", srcErr.Name)
+	r.Equal("method.go", filepath.Base(srcErr.Position.Filename))
+	r.Contains(srcErr.Error(), "as *big.Int value in argument to merged.typ2.Foo")
+
+	// each problem is reported once, at the output file or the source method
+	r.NotContains(err.Error(), "gocommand-")
+	r.NotContains(err.Error(), "-: #")
+}
+
+func TestGenerateInvalidRewrite(t *testing.T) {
+	r := require.New(t)
+
+	// the config is not validated by ReadConfig
+	config := &MergeConfig{
+		Sources: []*Source{{Type: "Impl1", Tag: "v0.0.1", Package: Package{ImportPath: "github.com/forta-network/go-merge-types/example/pkg1"}}},
+		Output: Output{
+			Type:    "Impl",
+			Package: "outpkg",
+			File:    "./outpkg/out.go",
+			Rewrite: rewrite.Rewriter{{Match: `^Foo([a-zA-Z]+BazOutput$`, Transform: "$"}},
+		},
+	}
+	r.NotPanics(func() {
+		_, err := Generate(config)
+		r.ErrorIs(err, ErrInvalidRewrite)
+		r.ErrorContains(err, "missing closing )")
+	})
+	r.Equal("FooBarBazOutput", config.Output.Rewrite.Rewrite("FooBarBazOutput"))
+}
+
+func TestGenerateRangeErrors(t *testing.T) {
+	r := require.New(t)
+
+	_, err := Generate(&MergeConfig{
+		Sources: []*Source{
+			{Tag: "v0.1", Range: ">=0.1.0 <0.2.0"},
+			{Tag: "v0.2", Range: ">=0.1.5 <0.3.0"},
+		},
+	})
+	r.ErrorIs(err, ErrOverlappingRanges)
+
+	_, err = Generate(&MergeConfig{
+		Sources: []*Source{
+			{Tag: "v0.1", Range: "~0.1.0"},
+		},
+	})
+	r.ErrorIs(err, ErrInvalidRange)
+	var srcErr *SourceError
+	r.ErrorAs(err, &srcErr)
+	r.Equal(0, srcErr.SourceIndex)
+
+	// the source of an empty range is never used
+	_, err = Generate(&MergeConfig{
+		Sources: []*Source{
+			{Tag: "v0.1", Range: ">=0.1.0 <0.2.0"},
+			{Tag: "v0.2", Range: ">=0.3.0 <0.2.0"},
+		},
+	})
+	r.ErrorIs(err, ErrInvalidRange)
+	r.ErrorAs(err, &srcErr)
+	r.Equal(1, srcErr.SourceIndex)
+	r.ErrorContains(err, "matches no version")
+
+	// an exact tag in the range of another source
+	_, err = Generate(&MergeConfig{
+		Sources: []*Source{
+			{Tag: "v0.1", Range: ">=0.1.0 <0.2.0"},
+			{Tag: "v0.1.5"},
+		},
+	})
+	r.ErrorIs(err, ErrOverlappingRanges)
+	r.ErrorContains(err, `tag "v0.1.5" is in ">=0.1.0 <0.2.0" (v0.1)`)
+}
+
+func TestMergeFallbacks(t *testing.T) {
+	r := require.New(t)
+
+	config, _, err := Run("example/example-gomergetypes.yml")
+	r.NoError(err)
+
+	variations := make(map[string]*Variation)
+	for _, method := range config.Output.Methods {
+		for _, variation := range method.Variations {
+			variations[method.Name+"@"+variation.Tag] = variation
+		}
+	}
+
+	// nearest older
+	r.True(variations["SingleReturnVal@v0.0.3"].Fallback)
+	r.Equal(1, variations["SingleReturnVal@v0.0.3"].SourceIndex)
+	r.False(variations["SingleReturnVal@v0.0.2"].Fallback)
+
+	// explicit
+	r.True(variations["NoReturnVal@v0.0.1"].Fallback)
+	r.Equal(2, variations["NoReturnVal@v0.0.1"].SourceIndex)
+
+	// no older source implements it
+	r.Nil(variations["ArrayMethod@v0.0.1"])
+}
+
+func TestAddFallbacksUnordered(t *testing.T) {
+	r := require.New(t)
+
+	// the sources are not listed in the version order
+	config := &MergeConfig{Sources: []*Source{{Tag: "v0.0.3"}, {Tag: "v0.0.1"}, {Tag: "v0.0.2"}}}
+	config.Output.Fallback.Policy = FallbackPolicyNearestOlder
+	methods := []*Method{
+		{Name: "Foo", Variations: []*Variation{{SourceIndex: 0, Tag: "v0.0.3"}}},
+		{Name: "Bar", Variations: []*Variation{{SourceIndex: 1, Tag: "v0.0.1"}}},
+		{Name: "Baz", Variations: []*Variation{{SourceIndex: 1, Tag: "v0.0.1"}, {SourceIndex: 2, Tag: "v0.0.2"}}},
+	}
+	r.NoError(addFallbacks(config, methods))
+
+	fallbacks := make(map[string]int)
+	for _, method := range methods {
+		for _, variation := range method.Variations {
+			if variation.Fallback {
+				fallbacks[method.Name+"@"+variation.Tag] = variation.SourceIndex
+			}
+		}
+	}
+	r.Equal(map[string]int{
+		// the newer versions are never used
+		"Bar@v0.0.2": 1,
+		"Bar@v0.0.3": 1,
+		"Baz@v0.0.3": 2,
+	}, fallbacks)
+}
+
+func TestAddFallbacksErrors(t *testing.T) {
+	sources := []*Source{{Tag: "v0.0.1"}, {Tag: "v0.0.2"}}
+	methods := []*Method{{Name: "Foo"}}
+
+	for _, fallback := range []Fallback{
+		{Policy: "newest"},
+		{Methods: map[string][]string{"Bar": {"v0.0.1"}}},
+		{Methods: map[string][]string{"Foo": {"v0.0.3"}}},
+	} {
+		config := &MergeConfig{Sources: sources}
+		config.Output.Fallback = fallback
+		require.ErrorIs(t, addFallbacks(config, methods), ErrInvalidFallback)
+	}
+
+	// the older sources cannot be found without the versions
+	config := &MergeConfig{Sources: []*Source{{Tag: "v0.0.1"}, {Tag: "latest"}}}
+	config.Output.Fallback.Policy = FallbackPolicyNearestOlder
+	require.ErrorIs(t, addFallbacks(config, methods), ErrInvalidFallback)
+}
+
+func TestGenerateUnknownFallbackMethod(t *testing.T) {
+	r := require.New(t)
+
+	// the methods are checked after loading but reported at the config position
+	_, _, err := Run("_testdata/unknown-fallback-gomergetypes.yml")
+	r.ErrorIs(err, ErrInvalidFallback)
+	var cfgErr *ConfigError
+	r.ErrorAs(err, &cfgErr)
+	r.Equal(19, cfgErr.Line)
+	r.Equal(7, cfgErr.Column)
+	r.Contains(err.Error(), "_testdata/unknown-fallback-gomergetypes.yml:19:7: invalid fallback: unknown method Qux")
+}
+
+func TestMergeTagMethods(t *testing.T) {
+	r := require.New(t)
+
+	config, _, err := Run("example/example-gomergetypes.yml")
+	r.NoError(err)
+
+	r.Len(config.Output.TagMethods, 3)
+	r.Equal("v0.0.1", config.Output.TagMethods[0].Tag)
+	// includes the fallbacks
+	r.Equal([]string{"Foo", "Bar", "SingleReturnVal", "NoReturnVal"}, config.Output.TagMethods[0].Methods)
+	r.Equal("v0.0.3", config.Output.TagMethods[2].Tag)
+	r.Contains(config.Output.TagMethods[2].Methods, "FooBarBaz")
+}
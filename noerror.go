@@ -0,0 +1,41 @@
+package merge
+
+import "fmt"
+
+// No error policies
+const (
+	NoErrorPolicyNone      = "none"
+	NoErrorPolicyPanic     = "panic"
+	NoErrorPolicyLastError = "lastError"
+)
+
+// setNoErrorPolicy keeps the methods without an error when none of the variations return
+// an error, so that the not implemented calls are handled by the configured policy.
+func setNoErrorPolicy(config *MergeConfig, methods []*Method) error {
+	policy := config.Output.NoError.Policy
+	switch policy {
+	case "", NoErrorPolicyNone:
+		return nil
+	case NoErrorPolicyPanic, NoErrorPolicyLastError:
+	default:
+		return fmt.Errorf("%w: unknown policy %q", ErrInvalidNoError, policy)
+	}
+
+	for _, method := range methods {
+		// shaped after the target interface
+		if method.Implements {
+			continue
+		}
+		method.NoError = true
+		for _, variation := range method.Variations {
+			if !variation.NoError {
+				method.NoError = false
+				break
+			}
+		}
+		if method.NoError {
+			method.NoErrorPolicy = policy
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package rewrite
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -13,19 +14,34 @@ type Rule struct {
 	compiled bool
 }
 
-func (rule *Rule) init() {
-	if rule.compiled {
-		return
+// Compile compiles the match pattern of the rule. The pattern should have one capture
+// group which replaces the $ in the transform.
+func (rule *Rule) Compile() error {
+	pattern, err := regexp.Compile(rule.Match)
+	if err != nil {
+		return err
 	}
-	rule.pattern = regexp.MustCompile(rule.Match)
+	if pattern.NumSubexp() != 1 {
+		return fmt.Errorf("pattern %q has %d capture groups: should have one", rule.Match, pattern.NumSubexp())
+	}
+	rule.pattern = pattern
 	rule.compiled = true
+	return nil
+}
+
+// init compiles the rule if needed and tells if the rule is valid.
+func (rule *Rule) init() bool {
+	return rule.compiled || rule.Compile() == nil
 }
 
 type Rewriter []*Rule
 
 func (rules Rewriter) Rewrite(input string) string {
 	for _, rule := range rules {
-		rule.init()
+		// the invalid rules are reported by Compile
+		if !rule.init() {
+			continue
+		}
 
 		results := rule.pattern.FindStringSubmatch(input)
 		if len(results) == 2 {
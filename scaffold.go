@@ -0,0 +1,256 @@
+package merge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/forta-network/go-merge-types/semver"
+	"golang.org/x/tools/go/packages"
+)
+
+// DefaultTagPattern matches the versioned dir names like v0_1_0.
+const DefaultTagPattern = `^v(\d+)_(\d+)_(\d+)$`
+
+// ScaffoldOptions describe where to find the sources of a starter config.
+type ScaffoldOptions struct {
+	// Dir is the parent dir of the versioned source packages.
+	Dir string
+	// TagPattern matches the versioned dir names. The captured parts are joined with dots
+	// to make the tag, prefixed with v if needed, e.g. v0_1_0 makes v0.1.0 with the default
+	// pattern.
+	TagPattern string
+	// TagTemplate makes the tag from the captured parts instead, e.g. v$1.$2.0 (see
+	// regexp.Expand).
+	TagTemplate string
+	// Type is the implementation type. It is the single type with a New<Type> constructor
+	// in each package if not specified.
+	Type string
+
+	OutputType    string
+	OutputPackage string
+	OutputFile    string
+}
+
+// versionedDir is a source package dir and the tag inferred from its name.
+type versionedDir struct {
+	path    string
+	tag     string
+	version *semver.Version // nil if the tag is not a semantic version
+}
+
+// Scaffold scans the versioned packages and returns a starter config with a source for each
+// package. The source dirs are relative to the config path.
+func Scaffold(configPath string, opts ScaffoldOptions) ([]byte, error) {
+	dirs, err := findVersionedDirs(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &MergeConfig{
+		Output: Output{
+			Type:    opts.OutputType,
+			Package: opts.OutputPackage,
+			File:    opts.OutputFile,
+		},
+	}
+	loader := NewLoader()
+	for i, dir := range dirs {
+		pkg, err := loader.Load(opts.Dir, &Package{SourceDir: dir.path})
+		if err != nil {
+			return nil, &SourceError{SourceIndex: i, Package: dir.path, Err: err}
+		}
+		typeName, err := findCandidate(i, pkg, opts.Type)
+		if err != nil {
+			return nil, err
+		}
+		sourceDir, err := relativeDir(configPath, dir.path)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(sourceDir, "..") {
+			sourceDir = "./" + sourceDir
+		}
+		config.Sources = append(config.Sources, &Source{
+			Type: typeName,
+			Tag:  dir.tag,
+			Package: Package{
+				ImportPath: pkg.PkgPath,
+				SourceDir:  filepath.ToSlash(sourceDir),
+			},
+		})
+	}
+
+	// merge the latest implementation type by default
+	if len(config.Output.Type) == 0 {
+		config.Output.Type = config.Sources[len(config.Sources)-1].Type
+	}
+	if len(config.Output.Package) == 0 {
+		config.Output.Package = "merged"
+	}
+	if len(config.Output.File) == 0 {
+		config.Output.File = fmt.Sprintf("./%s/out.go", config.Output.Package)
+	}
+
+	tmpl, err := template.New("scaffold").Parse(scaffoldTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, config); err != nil {
+		return nil, err
+	}
+
+	// the starter config should be valid as it is
+	if _, err := parseConfig(configPath, buffer.Bytes()); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// relativeDir finds the dir relative to the dir of the config.
+func relativeDir(configPath, dir string) (string, error) {
+	base, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		return "", err
+	}
+	target, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(base, target)
+}
+
+// findVersionedDirs finds the dirs which match the tag pattern, ordered by version. The dirs
+// are ordered by tag if some of the tags are not semantic versions.
+func findVersionedDirs(opts ScaffoldOptions) ([]*versionedDir, error) {
+	tagPattern := opts.TagPattern
+	if len(tagPattern) == 0 {
+		tagPattern = DefaultTagPattern
+	}
+	pattern, err := regexp.Compile(tagPattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTagPattern, err)
+	}
+	if pattern.NumSubexp() == 0 {
+		return nil, fmt.Errorf("%w: %s has no capture groups", ErrInvalidTagPattern, tagPattern)
+	}
+
+	entries, err := os.ReadDir(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []*versionedDir
+	for _, entry := range entries {
+		match := pattern.FindStringSubmatchIndex(entry.Name())
+		if !entry.IsDir() || match == nil {
+			continue
+		}
+		tag := makeTag(pattern, opts.TagTemplate, entry.Name(), match)
+		if len(tag) == 0 {
+			return nil, fmt.Errorf("%w: %s makes an empty tag", ErrInvalidTagPattern, entry.Name())
+		}
+		dir := &versionedDir{
+			path: filepath.Join(opts.Dir, entry.Name()),
+			tag:  tag,
+		}
+		if version, err := semver.Parse(tag); err == nil {
+			dir.version = &version
+		}
+		dirs = append(dirs, dir)
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("%w: no dir in %s matches %s", ErrPackageNotFound, opts.Dir, tagPattern)
+	}
+
+	semantic := true
+	for _, dir := range dirs {
+		semantic = semantic && dir.version != nil
+	}
+	sort.SliceStable(dirs, func(i, j int) bool {
+		if semantic {
+			return dirs[i].version.Compare(*dirs[j].version) < 0
+		}
+		return dirs[i].tag < dirs[j].tag
+	})
+	return dirs, nil
+}
+
+// makeTag makes the tag of a dir name from the captured parts of the match.
+func makeTag(pattern *regexp.Regexp, tagTemplate, name string, match []int) string {
+	if len(tagTemplate) > 0 {
+		return string(pattern.ExpandString(nil, tagTemplate, name, match))
+	}
+	var parts []string
+	for i := 2; i+1 < len(match); i += 2 {
+		// skip the groups which did not participate in the match
+		if match[i] >= 0 {
+			parts = append(parts, name[match[i]:match[i+1]])
+		}
+	}
+	tag := strings.Join(parts, ".")
+	if len(tag) > 0 && !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	return tag
+}
+
+// findCandidate finds the implementation type of a versioned package: the given type or
+// the single type which has a supported New<Type> constructor.
+func findCandidate(sourceIndex int, pkg *packages.Package, typeName string) (string, error) {
+	if len(typeName) > 0 {
+		if _, err := FindImplementation(sourceIndex, pkg, &Source{Type: typeName}); err != nil {
+			return "", err
+		}
+		return typeName, nil
+	}
+
+	var candidates []string
+	scope := pkg.Types.Scope()
+	// the names are sorted
+	for _, name := range scope.Names() {
+		if scope.Lookup("New"+name) == nil {
+			continue
+		}
+		if _, err := FindImplementation(sourceIndex, pkg, &Source{Type: name}); err == nil {
+			candidates = append(candidates, name)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", &SourceError{
+			SourceIndex: sourceIndex,
+			Package:     pkg.PkgPath,
+			Err:         fmt.Errorf("%w: no type with a New<Type> constructor", ErrImplementationNotFound),
+		}
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", &SourceError{
+			SourceIndex: sourceIndex,
+			Package:     pkg.PkgPath,
+			Err:         fmt.Errorf("%w: %s", ErrMultipleCandidates, strings.Join(candidates, ", ")),
+		}
+	}
+}
+
+const scaffoldTemplate = `sources:
+{{- range $source := .Sources}}
+  - type: {{$source.Type}}
+    tag: {{$source.Tag}}
+    package:
+      importPath: {{$source.Package.ImportPath}}
+      sourceDir: {{$source.Package.SourceDir}}
+{{- end}}
+
+output:
+  type: {{.Output.Type}}
+  package: {{.Output.Package}}
+  file: {{.Output.File}}
+`
@@ -0,0 +1,110 @@
+package merge
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/forta-network/go-merge-types/rewrite"
+)
+
+const schemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is the subset of JSON Schema which describes the config file.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// schemaRequired are the keys which the config validation requires.
+var schemaRequired = map[reflect.Type][]string{
+	reflect.TypeOf(Source{}):       {"type", "tag", "package"},
+	reflect.TypeOf(Output{}):       {"type", "package", "file"},
+	reflect.TypeOf(Implements{}):   {"importPath", "name"},
+	reflect.TypeOf(rewrite.Rule{}): {"match", "transform"},
+}
+
+// schemaEnums are the allowed values of the policy keys.
+var schemaEnums = map[reflect.Type]map[string][]string{
+	reflect.TypeOf(Fallback{}): {"policy": {FallbackPolicyNone, FallbackPolicyNearestOlder}},
+	reflect.TypeOf(NoError{}):  {"policy": {NoErrorPolicyNone, NoErrorPolicyPanic, NoErrorPolicyLastError}},
+}
+
+// Schema returns the JSON Schema of the config file. It is generated from the YAML keys
+// of the config types, so that the editors can complete and validate the config files.
+func Schema() ([]byte, error) {
+	defs := make(map[string]*jsonSchema)
+	schema := newSchema(reflect.TypeOf(ConfigFile{}), defs)
+	schema.Schema = schemaURI
+	schema.Title = "gomergetypes config"
+	schema.Defs = defs
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// newSchema describes the type. The structs are added to the definitions and referred to,
+// except for the config file itself.
+func newSchema(typ reflect.Type, defs map[string]*jsonSchema) *jsonSchema {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+
+	case reflect.Slice:
+		return &jsonSchema{Type: "array", Items: newSchema(typ.Elem(), defs)}
+
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: newSchema(typ.Elem(), defs)}
+
+	case reflect.Struct:
+		if typ == reflect.TypeOf(ConfigFile{}) {
+			return newStructSchema(typ, defs)
+		}
+		if _, ok := defs[typ.Name()]; !ok {
+			// reserve the name first for the recursive types
+			defs[typ.Name()] = nil
+			defs[typ.Name()] = newStructSchema(typ, defs)
+		}
+		return &jsonSchema{Ref: "#/$defs/" + typ.Name()}
+	}
+
+	return &jsonSchema{}
+}
+
+func newStructSchema(typ reflect.Type, defs map[string]*jsonSchema) *jsonSchema {
+	fields := make(map[string]reflect.Type)
+	collectFields(typ, fields)
+
+	schema := &jsonSchema{
+		Type:                 "object",
+		Properties:           make(map[string]*jsonSchema),
+		AdditionalProperties: false,
+		Required:             schemaRequired[typ],
+	}
+	for name, fieldType := range fields {
+		property := newSchema(fieldType, defs)
+		property.Enum = schemaEnums[typ][name]
+		schema.Properties[name] = property
+	}
+	return schema
+}
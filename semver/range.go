@@ -0,0 +1,187 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Range is a set of versions, such as ">=0.1.0 <0.2.0". Space separated comparisons
+// are intersected and "||" separated comparison sets are united.
+type Range struct {
+	input     string
+	sets      [][]Comparison
+	intervals []interval
+}
+
+// Comparison compares a version with the operator: ">=", "<=", ">", "<" or "=".
+type Comparison struct {
+	Op      string
+	Version Version
+}
+
+type bound struct {
+	version   Version
+	inclusive bool
+	set       bool
+}
+
+type interval struct {
+	lower bound
+	upper bound
+}
+
+// ParseRange parses a version range.
+func ParseRange(s string) (*Range, error) {
+	rng := &Range{input: s}
+	for _, set := range strings.Split(s, "||") {
+		comparisons := strings.Fields(set)
+		if len(comparisons) == 0 {
+			return nil, fmt.Errorf("invalid range %q: empty comparison set", s)
+		}
+		var (
+			in  interval
+			cmp []Comparison
+		)
+		for _, comparison := range comparisons {
+			op, version, err := parseComparison(comparison)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range %q: %v", s, err)
+			}
+			in = in.intersect(op, version)
+			cmp = append(cmp, Comparison{Op: op, Version: version})
+		}
+		rng.sets = append(rng.sets, cmp)
+		rng.intervals = append(rng.intervals, in)
+	}
+	return rng, nil
+}
+
+// MustParseRange parses a version range and panics on error.
+func MustParseRange(s string) *Range {
+	rng, err := ParseRange(s)
+	if err != nil {
+		panic(err)
+	}
+	return rng
+}
+
+func parseComparison(comparison string) (string, Version, error) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(comparison, op) {
+			version, err := Parse(comparison[len(op):])
+			return op, version, err
+		}
+	}
+	version, err := Parse(comparison)
+	return "=", version, err
+}
+
+func (in interval) intersect(op string, version Version) interval {
+	if op == ">" || op == ">=" || op == "=" {
+		lower := bound{version: version, inclusive: op != ">", set: true}
+		if !in.lower.set || lower.version.Compare(in.lower.version) > 0 ||
+			(lower.version.Compare(in.lower.version) == 0 && !lower.inclusive) {
+			in.lower = lower
+		}
+	}
+	if op == "<" || op == "<=" || op == "=" {
+		upper := bound{version: version, inclusive: op != "<", set: true}
+		if !in.upper.set || upper.version.Compare(in.upper.version) < 0 ||
+			(upper.version.Compare(in.upper.version) == 0 && !upper.inclusive) {
+			in.upper = upper
+		}
+	}
+	return in
+}
+
+func (in interval) contains(version Version) bool {
+	if in.lower.set {
+		c := version.Compare(in.lower.version)
+		if c < 0 || (c == 0 && !in.lower.inclusive) {
+			return false
+		}
+	}
+	if in.upper.set {
+		c := version.Compare(in.upper.version)
+		if c > 0 || (c == 0 && !in.upper.inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+func (in interval) empty() bool {
+	if !in.lower.set || !in.upper.set {
+		return false
+	}
+	c := in.lower.version.Compare(in.upper.version)
+	return c > 0 || (c == 0 && !(in.lower.inclusive && in.upper.inclusive))
+}
+
+// Contains tells if given version is in the range. Invalid versions are never in the range.
+func (rng *Range) Contains(version string) bool {
+	v, err := Parse(version)
+	if err != nil {
+		return false
+	}
+	for _, in := range rng.intervals {
+		if in.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sets returns the comparison sets of the range. A version is in the range if it
+// satisfies all comparisons of any set.
+func (rng *Range) Sets() [][]Comparison {
+	return rng.sets
+}
+
+// Unsatisfiable tells if a comparison set of the range matches no version, such as
+// ">=0.2.0 <0.1.0".
+func (rng *Range) Unsatisfiable() bool {
+	for _, in := range rng.intervals {
+		if in.empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlaps tells if there is any version which is in both ranges.
+func (rng *Range) Overlaps(other *Range) bool {
+	for _, a := range rng.intervals {
+		for _, b := range other.intervals {
+			in := a
+			if b.lower.set {
+				in = in.intersect(lowerOp(b.lower), b.lower.version)
+			}
+			if b.upper.set {
+				in = in.intersect(upperOp(b.upper), b.upper.version)
+			}
+			if !in.empty() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (rng *Range) String() string {
+	return rng.input
+}
+
+func lowerOp(b bound) string {
+	if b.inclusive {
+		return ">="
+	}
+	return ">"
+}
+
+func upperOp(b bound) string {
+	if b.inclusive {
+		return "<="
+	}
+	return "<"
+}
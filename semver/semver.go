@@ -0,0 +1,112 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version.
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	PreRelease string
+}
+
+// Parse parses a semantic version. The "v" prefix and the build metadata are optional.
+func Parse(s string) (Version, error) {
+	var version Version
+	input := strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	// ignore the build metadata
+	if i := strings.Index(input, "+"); i >= 0 {
+		input = input[:i]
+	}
+	if i := strings.Index(input, "-"); i >= 0 {
+		version.PreRelease = input[i+1:]
+		input = input[:i]
+		if len(version.PreRelease) == 0 {
+			return version, fmt.Errorf("invalid version %q: empty pre-release", s)
+		}
+	}
+
+	parts := strings.Split(input, ".")
+	if len(parts) != 3 {
+		return version, fmt.Errorf("invalid version %q: expected major.minor.patch", s)
+	}
+	nums := []*uint64{&version.Major, &version.Minor, &version.Patch}
+	for i, part := range parts {
+		num, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return version, fmt.Errorf("invalid version %q: %v", s, err)
+		}
+		*nums[i] = num
+	}
+	return version, nil
+}
+
+// Compare returns -1, 0 or 1 if the version is less than, equal to or greater than the other.
+func (version Version) Compare(other Version) int {
+	if c := compareNum(version.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareNum(version.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareNum(version.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePreRelease(version.PreRelease, other.PreRelease)
+}
+
+func (version Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+	if len(version.PreRelease) > 0 {
+		s += "-" + version.PreRelease
+	}
+	return s
+}
+
+func compareNum(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePreRelease compares the pre-release identifiers as described in the spec.
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.ParseUint(aParts[i], 10, 64)
+		bNum, bErr := strconv.ParseUint(bParts[i], 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareNum(aNum, bNum)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(aParts[i], bParts[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareNum(uint64(len(aParts)), uint64(len(bParts)))
+}
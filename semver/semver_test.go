@@ -0,0 +1,92 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	r := require.New(t)
+
+	ordered := []string{"0.1.0-alpha", "0.1.0-alpha.1", "0.1.0-alpha.beta", "0.1.0-beta.2", "0.1.0-beta.11", "0.1.0", "v0.1.7", "0.2.0+build", "1.0.0"}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, err := Parse(ordered[i])
+		r.NoError(err)
+		b, err := Parse(ordered[i+1])
+		r.NoError(err)
+		r.Equal(-1, a.Compare(b), "%s < %s", a, b)
+		r.Equal(1, b.Compare(a), "%s > %s", b, a)
+		r.Equal(0, a.Compare(a))
+	}
+
+	for _, invalid := range []string{"", "1", "1.2", "1.2.x", "1.2.3-"} {
+		_, err := Parse(invalid)
+		r.Error(err, invalid)
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	r := require.New(t)
+
+	rng, err := ParseRange(">=0.1.0 <0.2.0 || 1.0.0")
+	r.NoError(err)
+
+	r.True(rng.Contains("0.1.0"))
+	r.True(rng.Contains("0.1.7"))
+	r.True(rng.Contains("v0.1.7"))
+	r.True(rng.Contains("1.0.0"))
+	r.False(rng.Contains("0.2.0"))
+	r.False(rng.Contains("0.0.9"))
+	r.False(rng.Contains("1.0.1"))
+	r.False(rng.Contains("not a version"))
+
+	for _, invalid := range []string{"", ">=0.1.0 ||", "~0.1.0", ">=0.1"} {
+		_, err := ParseRange(invalid)
+		r.Error(err, invalid)
+	}
+}
+
+func TestRangeSets(t *testing.T) {
+	r := require.New(t)
+
+	rng := MustParseRange(">=0.1.0 <0.2.0-beta || 1.0.0")
+	r.Equal([][]Comparison{
+		{{Op: ">=", Version: Version{Minor: 1}}, {Op: "<", Version: Version{Minor: 2, PreRelease: "beta"}}},
+		{{Op: "=", Version: Version{Major: 1}}},
+	}, rng.Sets())
+}
+
+func TestRangeUnsatisfiable(t *testing.T) {
+	r := require.New(t)
+
+	r.False(MustParseRange(">=0.1.0 <0.2.0").Unsatisfiable())
+	r.False(MustParseRange(">=0.1.0 <=0.1.0").Unsatisfiable())
+	r.True(MustParseRange(">=0.2.0 <0.1.0").Unsatisfiable())
+	r.True(MustParseRange(">=0.1.0 <0.1.0").Unsatisfiable())
+	r.True(MustParseRange(">=1.0.0 || >0.2.0 <0.1.0").Unsatisfiable())
+}
+
+func TestRangeOverlaps(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		overlaps bool
+	}{
+		{a: ">=0.1.0 <0.2.0", b: ">=0.2.0 <0.3.0", overlaps: false},
+		{a: ">=0.1.0 <=0.2.0", b: ">=0.2.0 <0.3.0", overlaps: true},
+		{a: ">=0.1.0 <0.2.0", b: "0.1.5", overlaps: true},
+		{a: "<0.1.0", b: ">0.1.0", overlaps: false},
+		{a: "<0.1.0", b: ">=0.0.1", overlaps: true},
+		{a: ">=0.3.0", b: ">=0.1.0 <0.2.0 || >=1.0.0", overlaps: true},
+		{a: ">0.2.0 <0.1.0", b: ">=0.0.0", overlaps: false},
+	}
+
+	for _, testCase := range testCases {
+		r := require.New(t)
+
+		a := MustParseRange(testCase.a)
+		b := MustParseRange(testCase.b)
+		r.Equal(testCase.overlaps, a.Overlaps(b), "%s and %s", a, b)
+		r.Equal(testCase.overlaps, b.Overlaps(a), "%s and %s", b, a)
+	}
+}
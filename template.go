@@ -6,56 +6,218 @@ const codeTemplate = `
 package {{.Output.Package}}
 
 import (
-	import_fmt "fmt"
-	import_sync "sync"
-
-{{range $source := .Sources}}
+{{- template "reservedImports"}}
+{{- range $source := .Sources}}
 	{{$source.Package.Alias}} "{{$source.Package.ImportPath}}"
-{{end}}
-
-{{range $imp := .Output.Imports}}
+{{- end}}
+{{- range $imp := .Output.Imports}}
 	{{$imp}}
-{{end}}
+{{- end}}
 )
 
+{{if not .Output.SharedErrors}}
+// ErrNotImplemented matches the errors from the calls to the methods which are not
+// implemented for the current tag.
+var ErrNotImplemented = import_errors.New("not implemented")
+
+// NotImplementedError is returned from a merged type method if the implementation
+// of the current tag does not have the method.
+type NotImplementedError struct {
+	Type   string
+	Method string
+	Tag    string
+}
+
+func (err *NotImplementedError) Error() string {
+	return import_fmt.Sprintf("%s.%s not implemented (tag=%s)", err.Type, err.Method, err.Tag)
+}
+
+// Is makes the error match ErrNotImplemented.
+func (err *NotImplementedError) Is(target error) bool {
+	return target == ErrNotImplemented
+}
+{{end}}
 // {{.Output.Type}} is a new type which can multiplex calls to different implementation types.
 type {{.Output.Type}} struct {
-{{range $index, $source := .Sources}}
-	typ{{$index}} *{{$source.Package.Alias}}.{{$source.Type}}
-{{end}}
-	currTag string
-	mu import_sync.RWMutex
+{{- range $index, $source := .Sources}}
+	typ{{$index}} {{$source.ImplType}}
+{{- end}}
+{{if .Output.Atomic}}{{if .Output.IndexDispatch}}	currIndex import_atomic.Int32
+{{else}}	currTag import_atomic.Pointer[string]
+{{end}}{{else}}{{if .Output.IndexDispatch}}	currIndex int32
+{{else}}	currTag string
+{{end}}	mu import_sync.RWMutex
 	unsafe bool // default: false
-}
-
+{{end}}{{if eq .Output.NoError.Policy "lastError"}}	lastErr import_atomic.Pointer[error]
+{{end}}}
+{{if .Output.Implements.TypeName}}
+var _ {{.Output.Implements.TypeName}} = &{{.Output.Type}}{}
+{{end}}
 // New{{.Output.Type}} creates a new merged type.
-func New{{.Output.Type}}({{range $index, $arg := .Output.InitArgs}}{{if eq $index 0}}{{else}}, {{end}}{{$arg.Name}} {{$arg.Type}}{{end}}) (*{{.Output.Type}}, error) {
+func New{{.Output.Type}}({{template "params" (dict "Args" .Output.InitArgs)}}) (*{{.Output.Type}}, error) {
 	var (
 		mergedType {{.Output.Type}}
 		err error
 	)
-	mergedType.currTag = "{{.Output.DefaultTag}}"
-
+{{if .Output.Atomic}}{{if .Output.IndexDispatch}}	mergedType.currIndex.Store(tagIndexesFor{{.Output.Type}}["{{.Output.DefaultTag}}"])
+{{else}}	defaultTag := "{{.Output.DefaultTag}}"
+	mergedType.currTag.Store(&defaultTag)
+{{end}}{{else}}{{if .Output.IndexDispatch}}	mergedType.currIndex = tagIndexesFor{{.Output.Type}}["{{.Output.DefaultTag}}"]
+{{else}}	mergedType.currTag = "{{.Output.DefaultTag}}"
+{{end}}{{end}}
 {{range $sourceIndex, $source := .Sources}}
-	mergedType.typ{{$sourceIndex}}, err = {{$source.Package.Alias}}.New{{$source.Type}}({{range $argIndex, $arg := $source.InitArgs}}{{if eq $argIndex 0}}{{else}}, {{end}}{{$arg.Name}}{{end}})
+	mergedType.typ{{$sourceIndex}}, err = {{$source.Package.Alias}}.New{{$source.Type}}{{$source.ConstructorTypeArgs}}({{template "forwardArgs" $source.InitArgs}})
 	if err != nil {
-		return nil, import_fmt.Errorf("failed to initialize {{$source.Package.Alias}}.{{$source.Type}}: %v", err)
+		return nil, import_fmt.Errorf("failed to initialize {{$source.Package.Alias}}.{{$source.Type}}: %w", err)
 	}
 {{end}}
 
 	return &mergedType, nil
 }
 
-// IsKnownTagFor{{.Output.Type}} tells if given tag is a known tag.
-func IsKnownTagFor{{.Output.Type}}(tag string) bool {
+{{if .Output.IndexDispatch}}
+// tagsFor{{.Output.Type}} are the source tags by the dispatch indexes.
+var tagsFor{{.Output.Type}} = []string{ {{range $index, $source := .Sources}}{{if $index}}, {{end}}"{{$source.Tag}}"{{end}} }
+
+// tagIndexesFor{{.Output.Type}} are the dispatch indexes of the source tags.
+var tagIndexesFor{{.Output.Type}} = map[string]int32{
+{{- range $index, $source := .Sources}}
+	"{{$source.Tag}}": {{$index}},
+{{- end}}
+}
+{{end}}
+// ResolveTagFor{{.Output.Type}} finds the source tag which is equal to given tag or has a version range containing it.
+func ResolveTagFor{{.Output.Type}}(tag string) (string, bool) {
 {{range $tag := .Output.KnownTags}}
 	if tag == "{{$tag}}" {
-		return true
+		return tag, true
 	}
 {{end}}
-	return false
+{{if .Output.HasRanges}}
+	version, ok := parseVersionFor{{.Output.Type}}(tag)
+	if !ok {
+		return "", false
+	}
+{{range $source := .Sources}}{{range $set := $source.RangeSets}}
+	if {{range $index, $comparison := $set}}{{if $index}} && {{end}}compareVersionsFor{{$.Output.Type}}(version, versionFor{{$.Output.Type}}{ {{$comparison.Version.Major}}, {{$comparison.Version.Minor}}, {{$comparison.Version.Patch}}, "{{$comparison.Version.PreRelease}}"}) {{if eq $comparison.Op "="}}=={{else}}{{$comparison.Op}}{{end}} 0{{end}} {
+		return "{{$source.Tag}}", true
+	}
+{{end}}{{end}}
+{{end}}
+	return "", false
+}
+{{if .Output.HasRanges}}
+// versionFor{{.Output.Type}} is a semantic version of a tag.
+type versionFor{{.Output.Type}} struct {
+	major, minor, patch uint64
+	preRelease          string
+}
+
+// parseVersionFor{{.Output.Type}} parses a semantic version. The "v" prefix and the build metadata are optional.
+func parseVersionFor{{.Output.Type}}(s string) (version versionFor{{.Output.Type}}, ok bool) {
+	input := import_strings.TrimPrefix(import_strings.TrimSpace(s), "v")
+	if i := import_strings.Index(input, "+"); i >= 0 {
+		input = input[:i]
+	}
+	if i := import_strings.Index(input, "-"); i >= 0 {
+		version.preRelease = input[i+1:]
+		input = input[:i]
+		if len(version.preRelease) == 0 {
+			return version, false
+		}
+	}
+	parts := import_strings.Split(input, ".")
+	if len(parts) != 3 {
+		return version, false
+	}
+	nums := []*uint64{&version.major, &version.minor, &version.patch}
+	for i, part := range parts {
+		num, err := import_strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return version, false
+		}
+		*nums[i] = num
+	}
+	return version, true
+}
+
+// compareVersionsFor{{.Output.Type}} returns -1, 0 or 1 if a is less than, equal to or greater than b.
+func compareVersionsFor{{.Output.Type}}(a, b versionFor{{.Output.Type}}) int {
+	if c := compareUintsFor{{.Output.Type}}(a.major, b.major); c != 0 {
+		return c
+	}
+	if c := compareUintsFor{{.Output.Type}}(a.minor, b.minor); c != 0 {
+		return c
+	}
+	if c := compareUintsFor{{.Output.Type}}(a.patch, b.patch); c != 0 {
+		return c
+	}
+	// a version without pre-release identifiers has the higher precedence
+	switch {
+	case a.preRelease == b.preRelease:
+		return 0
+	case len(a.preRelease) == 0:
+		return 1
+	case len(b.preRelease) == 0:
+		return -1
+	}
+	aParts, bParts := import_strings.Split(a.preRelease, "."), import_strings.Split(b.preRelease, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := import_strconv.ParseUint(aParts[i], 10, 64)
+		bNum, bErr := import_strconv.ParseUint(bParts[i], 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareUintsFor{{.Output.Type}}(aNum, bNum)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = import_strings.Compare(aParts[i], bParts[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareUintsFor{{.Output.Type}}(uint64(len(aParts)), uint64(len(bParts)))
 }
 
+// compareUintsFor{{.Output.Type}} returns -1, 0 or 1 if a is less than, equal to or greater than b.
+func compareUintsFor{{.Output.Type}}(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+{{end}}
+// IsKnownTagFor{{.Output.Type}} tells if given tag is a known tag.
+func IsKnownTagFor{{.Output.Type}}(tag string) bool {
+	_, ok := ResolveTagFor{{.Output.Type}}(tag)
+	return ok
+}
+{{if .Output.Atomic}}
+// Use sets the used implementation to given tag.
+func (merged *{{.Output.Type}}) Use(tag string) (changed bool) {
+	// use the default tag if the provided tag is unknown
+	tag, ok := ResolveTagFor{{.Output.Type}}(tag)
+	if !ok {
+		tag = "{{.Output.DefaultTag}}"
+	}
+{{if .Output.IndexDispatch}}	index := tagIndexesFor{{.Output.Type}}[tag]
+	return merged.currIndex.Swap(index) != index
+{{else}}	return *merged.currTag.Swap(&tag) != tag
+{{end}}}
+
+// Unsafe has no effect since the tag is switched atomically.
+func (merged *{{.Output.Type}}) Unsafe() {}
+
+// Safe has no effect since the tag is switched atomically.
+func (merged *{{.Output.Type}}) Safe() {}
+{{else}}
 // Use sets the used implementation to given tag.
 func (merged *{{.Output.Type}}) Use(tag string) (changed bool) {
 	if !merged.unsafe {
@@ -63,12 +225,16 @@ func (merged *{{.Output.Type}}) Use(tag string) (changed bool) {
 		defer merged.mu.Unlock()
 	}
 	// use the default tag if the provided tag is unknown
-	if !IsKnownTagFor{{.Output.Type}}(tag) {
+	tag, ok := ResolveTagFor{{.Output.Type}}(tag)
+	if !ok {
 		tag = "{{.Output.DefaultTag}}"
 	}
-	changed = merged.currTag != tag
+{{if .Output.IndexDispatch}}	index := tagIndexesFor{{.Output.Type}}[tag]
+	changed = merged.currIndex != index
+	merged.currIndex = index
+{{else}}	changed = merged.currTag != tag
 	merged.currTag = tag
-	return
+{{end}}	return
 }
 
 // Unsafe disables the mutex.
@@ -80,48 +246,311 @@ func (merged *{{.Output.Type}}) Unsafe() {
 func (merged *{{.Output.Type}}) Safe() {
 	merged.unsafe = false
 }
+{{end}}{{if eq .Output.NoError.Policy "lastError"}}
+// LastError returns the error of the last call to a method which does not return an error.
+func (merged *{{.Output.Type}}) LastError() error {
+	if err := merged.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (merged *{{.Output.Type}}) setLastError(err error) {
+	merged.lastErr.Store(&err)
+}
+{{end}}
+// methodsByTagFor{{.Output.Type}} are the supported methods of each tag.
+var methodsByTagFor{{.Output.Type}} = map[string][]string{
+{{- range $tagMethods := .Output.TagMethods}}
+	"{{$tagMethods.Tag}}": { {{range $index, $name := $tagMethods.Methods}}{{if eq $index 0}}{{else}}, {{end}}"{{$name}}"{{end}} },
+{{- end}}
+}
+
+// MethodsForTagFor{{.Output.Type}} returns the methods which given tag supports. Unknown tags are
+// treated as the default tag.
+func MethodsForTagFor{{.Output.Type}}(tag string) []string {
+	tag, ok := ResolveTagFor{{.Output.Type}}(tag)
+	if !ok {
+		tag = "{{.Output.DefaultTag}}"
+	}
+	return append([]string(nil), methodsByTagFor{{.Output.Type}}[tag]...)
+}
 
+// Supports tells if the implementation of the current tag supports given method.
+func (merged *{{.Output.Type}}) Supports(method string) bool {
+{{if .Output.Atomic}}{{if .Output.IndexDispatch}}	return merged.SupportsForTag(tagsFor{{.Output.Type}}[merged.currIndex.Load()], method)
+{{else}}	return merged.SupportsForTag(*merged.currTag.Load(), method)
+{{end}}{{else}}	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+{{if .Output.IndexDispatch}}	return merged.SupportsForTag(tagsFor{{.Output.Type}}[merged.currIndex], method)
+{{else}}	return merged.SupportsForTag(merged.currTag, method)
+{{end}}{{end}}}
+
+// SupportsForTag tells if the implementation of given tag supports given method.
+func (merged *{{.Output.Type}}) SupportsForTag(tag, method string) bool {
+	for _, supported := range MethodsForTagFor{{.Output.Type}}(tag) {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+{{if .Output.Interface.Name}}{{if not .Output.Interface.File}}{{template "interface" .}}{{end}}{{end}}{{if .Output.Fake.Name}}{{if not .Output.Fake.File}}{{template "fake" .}}{{end}}{{end}}{{if .Output.View.Name}}{{template "view" .}}{{end}}
 {{range $method := .Output.Methods}}
-{{if or $method.NoReturn $method.SingleReturn}}{{else}}
+{{if or $method.NoReturn $method.SingleReturn $method.MultiReturn}}{{else}}
 // {{$method.ReturnType.Name}} is a merged return type.
 type {{$method.ReturnType.Name}} struct {
-{{range $retField := $method.ReturnType.Fields}}
+{{- range $retField := $method.ReturnType.Fields}}
 	{{$retField.Name}} {{$retField.Type}}
-{{end}}
+{{- end}}
 }{{end}}
 
 // {{$method.Name}} multiplexes to different implementations of the method.
-func (merged *{{$.Output.Type}}) {{$method.Name}}({{range $index, $arg := $method.Args}}{{if eq $index 0}}{{else}}, {{end}}{{$arg.Name}} {{$arg.Type}}{{end}}) {{if $method.NoReturn}}(err error){{else}}(retVal {{if eq $method.SingleReturn false}}*{{end}}{{$method.ReturnType.Name}}, err error){{end}} {
-	if !merged.unsafe {
+func (merged *{{$.Output.Type}}) {{template "signature" $method}} {
+{{if $.Output.Atomic}}{{if $.Output.IndexDispatch}}	currIndex := merged.currIndex.Load()
+
+{{template "dispatch" (dict "Type" $.Output.Type "Method" $method "Receiver" "merged" "State" "merged" "Tag" (printf "tagsFor%s[currIndex]" $.Output.Type) "Index" "currIndex")}}{{else}}	currTag := *merged.currTag.Load()
+
+{{template "dispatch" (dict "Type" $.Output.Type "Method" $method "Receiver" "merged" "State" "merged" "Tag" "currTag")}}{{end}}{{else}}	if !merged.unsafe {
 		merged.mu.RLock()
 		defer merged.mu.RUnlock()
 	}
 
-{{if eq $method.SingleReturn false}}{{if eq $method.NoReturn false}}
+{{if $.Output.IndexDispatch}}{{template "dispatch" (dict "Type" $.Output.Type "Method" $method "Receiver" "merged" "State" "merged" "Tag" (printf "tagsFor%s[merged.currIndex]" $.Output.Type) "Index" "merged.currIndex")}}{{else}}{{template "dispatch" (dict "Type" $.Output.Type "Method" $method "Receiver" "merged" "State" "merged" "Tag" "merged.currTag")}}{{end}}{{end}}
+}
+{{end}}
+`
+
+const dispatchTemplate = `{{define "dispatch"}}{{$type := .Type}}{{$method := .Method}}{{$recv := .Receiver}}{{$state := .State}}{{$tag := .Tag}}{{if eq $method.NoErrorPolicy "lastError"}}
+	{{$state}}.lastErr.Store(nil)
+{{end}}{{if not (or $method.SingleReturn $method.NoReturn $method.MultiReturn)}}
 	retVal = &{{$method.ReturnType.Name}}{}
+{{end}}
+{{if .Index}}
+	switch {{.Index}} {
+{{range $variation := $method.Variations}}
+	case {{$variation.TagIndex}}:
+{{template "variationCall" (dict "Method" $method "Variation" $variation "Receiver" $recv)}}
+{{end}}
+	}
+{{else}}
+{{range $variation := $method.Variations}}
+	if {{$tag}} == "{{$variation.Tag}}" {
+{{template "variationCall" (dict "Method" $method "Variation" $variation "Receiver" $recv)}}
+	}
 {{end}}{{end}}
 
-{{range $variation := $method.Variations}}
-	if merged.currTag == "{{$variation.Tag}}" {
-		{{if $variation.NoReturn}}{{else}}{{if $variation.OnlyError}}methodErr := {{else}}val, methodErr := {{end}}{{end}}merged.typ{{$variation.SourceIndex}}.{{$variation.Name}}({{range $index, $arg := $variation.Args}}{{if eq $index 0}}{{else}}, {{end}}{{$arg.Name}}{{end}})
-{{if eq $variation.NoReturn false}}
+{{if eq $method.NoErrorPolicy "panic"}}	panic(&NotImplementedError{Type: "{{$type}}", Method: "{{$method.Name}}", Tag: {{$tag}}}){{else if eq $method.NoErrorPolicy "lastError"}}	{{$state}}.setLastError(&NotImplementedError{Type: "{{$type}}", Method: "{{$method.Name}}", Tag: {{$tag}}}){{if not $method.NoReturn}}
+	return{{end}}{{else}}	err = &NotImplementedError{Type: "{{$type}}", Method: "{{$method.Name}}", Tag: {{$tag}}}
+	return{{end}}{{end}}
+
+{{define "variationCall"}}{{$method := .Method}}{{$variation := .Variation}}		{{if $variation.NoReturn}}{{else}}{{range $index, $value := $variation.Values}}{{if $index}}, {{end}}{{$value}}{{end}}{{if not $variation.NoError}}{{if $variation.Values}}, {{end}}methodErr{{end}} := {{end}}{{.Receiver}}.typ{{$variation.SourceIndex}}.{{$variation.Name}}({{template "forwardArgs" $variation.Args}})
+{{if not $variation.NoError}}
 		if methodErr != nil {
 			err = methodErr
 			return
 		}{{end}}
 {{if $method.SingleReturn}}
 		retVal = val
+{{else if $method.MultiReturn}}
+		{{template "retVals" $method}} = {{range $index, $value := $variation.Values}}{{if $index}}, {{end}}{{$value}}{{end}}
 {{else}}
-{{range $retField := $variation.ReturnedFields}}
-		retVal.{{$retField.Name}} = val{{if $variation.MergeReturnedStruct}}.{{$retField.Name}}{{else}}{{end}}
+{{- range $index, $retField := $variation.ReturnedFields}}
+		retVal.{{$retField.Name}} = {{if $variation.MergeReturnedStruct}}val.{{$retField.Name}}{{else}}{{index $variation.Values $index}}{{end}}
+{{- end}}
+{{end}}
+		return{{end}}`
+
+const signatureTemplate = `
+{{define "reservedImports"}}
+	import_errors "errors"
+	import_fmt "fmt"
+	import_strconv "strconv"
+	import_strings "strings"
+	import_sync "sync"
+	import_atomic "sync/atomic"
+{{- end}}
+{{define "params"}}{{range $index, $arg := .Args}}{{if eq $index 0}}{{else}}, {{end}}{{$arg.Name}} {{template "paramType" $arg}}{{end}}{{end}}
+{{define "paramType"}}{{if .Ellipsis}}...{{slice .Type 2}}{{else}}{{.Type}}{{end}}{{end}}
+{{define "argNames"}}{{range $index, $arg := .Args}}{{if eq $index 0}}{{else}}, {{end}}{{$arg.Name}}{{if $arg.Ellipsis}}...{{end}}{{end}}{{end}}
+{{define "forwardArgs"}}{{range $index, $arg := .}}{{if eq $index 0}}{{else}}, {{end}}{{$arg.Name}}{{if $arg.Variadic}}...{{end}}{{end}}{{end}}
+{{define "results"}}{{if and .NoReturn .NoError}}{{else}} ({{template "namedResults" .}}){{end}}{{end}}
+{{define "namedResults"}}{{if .NoReturn}}{{else if .MultiReturn}}{{range $index, $field := .ReturnType.Fields}}{{if $index}}, {{end}}{{template "retVal" $index}} {{$field.Type}}{{end}}{{else}}retVal {{if eq .SingleReturn false}}*{{end}}{{.ReturnType.Name}}{{end}}{{if not .NoError}}{{if not .NoReturn}}, {{end}}err error{{end}}{{end}}
+{{define "resultTypes"}}{{if .NoReturn}}{{if not .NoError}} error{{end}}{{else if .MultiReturn}} ({{range $index, $field := .ReturnType.Fields}}{{if $index}}, {{end}}{{$field.Type}}{{end}}{{if not .NoError}}, error{{end}}){{else if .NoError}} {{if eq .SingleReturn false}}*{{end}}{{.ReturnType.Name}}{{else}} ({{if eq .SingleReturn false}}*{{end}}{{.ReturnType.Name}}, error){{end}}{{end}}
+{{define "returnValues"}}{{if .NoReturn}}{{else}}{{template "retVals" .}}{{if not .NoError}}, {{end}}{{end}}{{if not .NoError}}err{{end}}{{end}}
+{{define "retVal"}}retVal{{if .}}{{.}}{{end}}{{end}}
+{{define "retVals"}}{{if .MultiReturn}}{{range $index, $field := .ReturnType.Fields}}{{if $index}}, {{end}}{{template "retVal" $index}}{{end}}{{else}}retVal{{end}}{{end}}
+{{define "signature"}}{{.Name}}({{template "params" .}}){{template "results" .}}{{end}}
+`
+
+const interfaceTemplate = `{{define "interface"}}
+// {{.Output.Interface.Name}} is an interface for {{.Output.Type}}.
+type {{.Output.Interface.Name}} interface {
+	Use(tag string) (changed bool)
+{{if eq .Output.NoError.Policy "lastError"}}	LastError() error
+{{end}}
+{{- range $method := .Output.Methods}}
+	{{template "signature" $method}}
+{{- end}}
+}
+
+var _ {{.Output.Interface.Name}} = &{{.Output.Type}}{}
+{{end}}`
+
+const interfaceFileTemplate = `
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package {{.Output.Package}}
+
+import (
+{{- template "reservedImports"}}
+{{- range $source := .Sources}}
+	{{$source.Package.Alias}} "{{$source.Package.ImportPath}}"
+{{- end}}
+{{- range $imp := .Output.Imports}}
+	{{$imp}}
+{{- end}}
+)
+{{template "interface" .}}
+`
+
+const fakeTemplate = `{{define "fake"}}{{$fake := .Output.Fake.Name}}
+// {{$fake}} is a fake {{.Output.Type}} which records the calls and returns the programmed values.
+type {{$fake}} struct {
+	mu import_sync.Mutex
+
+	UseCalls []string
+	UseFunc func(tag string) (changed bool)
+{{if eq .Output.NoError.Policy "lastError"}}	LastErr error
+{{end}}{{range $method := .Output.Methods}}
+	{{$method.Name}}Calls []{{$fake}}{{$method.Name}}Call
+	{{$method.Name}}Func func({{template "params" $method}}){{template "results" $method}}
 {{end}}
+}
+{{if .Output.Interface.Name}}
+var _ {{.Output.Interface.Name}} = &{{$fake}}{}
 {{end}}
-		return
+// Use records the call and returns the programmed value.
+func (fake *{{$fake}}) Use(tag string) (changed bool) {
+	fake.mu.Lock()
+	fake.UseCalls = append(fake.UseCalls, tag)
+	fn := fake.UseFunc
+	fake.mu.Unlock()
+	if fn != nil {
+		return fn(tag)
 	}
+	return
+}
+{{if eq .Output.NoError.Policy "lastError"}}
+// LastError returns the programmed error.
+func (fake *{{$fake}}) LastError() error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return fake.LastErr
+}
 {{end}}
+{{range $method := .Output.Methods}}
+// {{$fake}}{{$method.Name}}Call is a recorded call to {{$fake}}.{{$method.Name}}.
+type {{$fake}}{{$method.Name}}Call struct {
+{{- range $arg := $method.Args}}
+	{{export $arg.Name}} {{$arg.Type}}
+{{- end}}
+}
 
-	err = import_fmt.Errorf("{{$.Output.Type}}.{{$method.Name}} not implemented (tag=%s)", merged.currTag)
+// {{$method.Name}} records the call and returns the programmed values.
+func (fake *{{$fake}}) {{template "signature" $method}} {
+	fake.mu.Lock()
+	fake.{{$method.Name}}Calls = append(fake.{{$method.Name}}Calls, {{$fake}}{{$method.Name}}Call{
+{{- range $arg := $method.Args}}
+		{{export $arg.Name}}: {{$arg.Name}},
+{{- end}}
+	})
+	fn := fake.{{$method.Name}}Func
+	fake.mu.Unlock()
+	if fn != nil {
+{{if and $method.NoReturn $method.NoError}}		fn({{template "argNames" $method}})
+	}
+{{else}}		return fn({{template "argNames" $method}})
+	}
 	return
+{{end}}}
+{{if and $method.NoReturn $method.NoError}}{{else}}
+// {{$method.Name}}Returns programs the values to return from {{$method.Name}}.
+func (fake *{{$fake}}) {{$method.Name}}Returns({{template "namedResults" $method}}) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.{{$method.Name}}Func = func({{template "params" $method}}){{template "resultTypes" $method}} {
+		return {{template "returnValues" $method}}
+	}
 }
-{{end}}
+{{end}}{{end}}
+{{end}}`
+
+const fakeFileTemplate = `
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package {{.Output.Package}}
+
+import (
+{{- template "reservedImports"}}
+{{- range $source := .Sources}}
+	{{$source.Package.Alias}} "{{$source.Package.ImportPath}}"
+{{- end}}
+{{- range $imp := .Output.Imports}}
+	{{$imp}}
+{{- end}}
+)
+{{template "fake" .}}
 `
+
+const viewTemplate = `{{define "view"}}{{$view := .Output.View.Name}}
+// {{$view}} is a view of {{.Output.Type}} which uses a fixed tag. It is safe to use views
+// with different tags concurrently.
+type {{$view}} struct {
+	merged *{{.Output.Type}}
+	tag    string
+{{if .Output.IndexDispatch}}	index  int32
+{{end}}{{if eq .Output.NoError.Policy "lastError"}}	lastErr import_atomic.Pointer[error]
+{{end}}}
+
+// WithTag returns a view which uses given tag regardless of the current tag. Unknown tags are
+// treated as the default tag.
+func (merged *{{.Output.Type}}) WithTag(tag string) *{{$view}} {
+	tag, ok := ResolveTagFor{{.Output.Type}}(tag)
+	if !ok {
+		tag = "{{.Output.DefaultTag}}"
+	}
+	return &{{$view}}{merged: merged, tag: tag{{if .Output.IndexDispatch}}, index: tagIndexesFor{{.Output.Type}}[tag]{{end}}}
+}
+
+// Tag returns the tag of the view.
+func (view *{{$view}}) Tag() string {
+	return view.tag
+}
+
+// Supports tells if the implementation of the view tag supports given method.
+func (view *{{$view}}) Supports(method string) bool {
+	return view.merged.SupportsForTag(view.tag, method)
+}
+{{if eq .Output.NoError.Policy "lastError"}}
+// LastError returns the error of the last call to a method of the view which does not
+// return an error.
+func (view *{{$view}}) LastError() error {
+	if err := view.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (view *{{$view}}) setLastError(err error) {
+	view.lastErr.Store(&err)
+}
+{{end}}{{range $method := .Output.Methods}}
+// {{$method.Name}} calls the implementation of the view tag.
+func (view *{{$view}}) {{template "signature" $method}} {
+{{if $.Output.IndexDispatch}}{{template "dispatch" (dict "Type" $.Output.Type "Method" $method "Receiver" "view.merged" "State" "view" "Tag" "view.tag" "Index" "view.index")}}{{else}}{{template "dispatch" (dict "Type" $.Output.Type "Method" $method "Receiver" "view.merged" "State" "view" "Tag" "view.tag")}}{{end}}
+}
+{{end}}
+{{end}}`
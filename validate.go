@@ -0,0 +1,299 @@
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/forta-network/go-merge-types/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is a problem at a position of the config file.
+type ConfigError struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (cfgErr *ConfigError) Error() string {
+	if cfgErr.Line == 0 {
+		return fmt.Sprintf("%s: %v", cfgErr.File, cfgErr.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %v", cfgErr.File, cfgErr.Line, cfgErr.Column, cfgErr.Err)
+}
+
+func (cfgErr *ConfigError) Unwrap() error {
+	return cfgErr.Err
+}
+
+// parseConfig decodes the config file and reports all of the problems in it.
+func parseConfig(configPath string, b []byte) (*ConfigFile, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	var file ConfigFile
+	if len(doc.Content) == 0 {
+		return &file, nil
+	}
+	root := doc.Content[0]
+
+	v := &configValidator{file: configPath}
+	v.checkFields(root, reflect.TypeOf(file))
+	if err := root.Decode(&file); err != nil {
+		v.errs = append(v.errs, err)
+		return nil, errors.Join(v.errs...)
+	}
+
+	// the top-level output needs the top-level sources
+	output := mappingValue(root, "output")
+	switch {
+	case len(file.Sources) > 0:
+		v.validate(&file.MergeConfig, root)
+	case output != nil:
+		v.report(output, fmt.Errorf("%w: sources of the top-level output", ErrMissingConfigField))
+	case len(file.Targets) == 0:
+		v.reportMissing(root, "sources")
+	}
+	targets := mappingValue(root, "targets")
+	for i, config := range file.Targets {
+		v.validate(config, sequenceItem(targets, i))
+	}
+
+	if len(v.errs) > 0 {
+		return nil, errors.Join(v.errs...)
+	}
+	return &file, nil
+}
+
+type configValidator struct {
+	file string
+	errs []error
+}
+
+func (v *configValidator) report(node *yaml.Node, err error) {
+	v.errs = append(v.errs, v.pos(node).wrap(err))
+}
+
+func (v *configValidator) pos(node *yaml.Node) configPos {
+	pos := configPos{file: v.file}
+	if node != nil {
+		pos.line = node.Line
+		pos.column = node.Column
+	}
+	return pos
+}
+
+// configPos is a position in the config file, kept for the problems which can only be
+// found after loading the sources.
+type configPos struct {
+	file         string
+	line, column int
+}
+
+// wrap makes a config error at the position, if the position is known.
+func (pos configPos) wrap(err error) error {
+	if len(pos.file) == 0 {
+		return err
+	}
+	return &ConfigError{File: pos.file, Line: pos.line, Column: pos.column, Err: err}
+}
+
+// checkFields reports the keys which do not match the fields of given type.
+func (v *configValidator) checkFields(node *yaml.Node, typ reflect.Type) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := make(map[string]reflect.Type)
+		collectFields(typ, fields)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			// merge keys are resolved by the decoder
+			if key.Value == "<<" {
+				v.checkFields(value, typ)
+				continue
+			}
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				v.report(key, fmt.Errorf("%w: %s", ErrUnknownConfigField, key.Value))
+				continue
+			}
+			v.checkFields(value, fieldType)
+		}
+
+	case typ.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for _, item := range node.Content {
+			v.checkFields(item, typ.Elem())
+		}
+
+	case typ.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			v.checkFields(node.Content[i], typ.Elem())
+		}
+	}
+}
+
+// collectFields finds the YAML keys of the struct fields like the decoder does.
+func collectFields(typ reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("yaml")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if opts == "inline" {
+			collectFields(field.Type, fields)
+			continue
+		}
+		if len(name) == 0 {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+}
+
+// validate reports the problems of a merge target which would fail or be ignored later.
+func (v *configValidator) validate(config *MergeConfig, node *yaml.Node) {
+	sources := mappingValue(node, "sources")
+	if len(config.Sources) == 0 {
+		v.reportMissing(orNode(sources, node), "sources")
+	}
+	tags := make(map[string]int)
+	for i, source := range config.Sources {
+		sourceNode := sequenceItem(sources, i)
+		if len(source.Type) == 0 {
+			v.reportMissing(sourceNode, "type")
+		}
+		if len(source.Tag) == 0 {
+			v.reportMissing(sourceNode, "tag")
+		} else if j, ok := tags[source.Tag]; ok {
+			v.report(mappingValue(sourceNode, "tag"), fmt.Errorf("%w: %s is also the tag of source %d", ErrDuplicateTag, source.Tag, j))
+		} else {
+			tags[source.Tag] = i
+		}
+		// import path is optional if the source dir is specified
+		if len(source.Package.ImportPath) == 0 && len(source.Package.SourceDir) == 0 {
+			v.reportMissing(orNode(mappingValue(sourceNode, "package"), sourceNode), "importPath")
+		}
+		if len(source.Range) > 0 {
+			rangeNode := mappingValue(sourceNode, "range")
+			rng, err := semver.ParseRange(source.Range)
+			switch {
+			case err != nil:
+				v.report(rangeNode, fmt.Errorf("%w: %v", ErrInvalidRange, err))
+			case rng.Unsatisfiable():
+				v.report(rangeNode, fmt.Errorf("%w: %q matches no version", ErrInvalidRange, source.Range))
+			}
+		}
+	}
+
+	output := orNode(mappingValue(node, "output"), node)
+	if len(config.Output.Type) == 0 {
+		v.reportMissing(output, "type")
+	}
+	if len(config.Output.Package) == 0 {
+		v.reportMissing(output, "package")
+	}
+	if len(config.Output.File) == 0 {
+		v.reportMissing(output, "file")
+	}
+	if defaultTag := config.Output.DefaultTag; len(defaultTag) > 0 {
+		if _, ok := tags[defaultTag]; !ok {
+			v.report(mappingValue(output, "defaultTag"), fmt.Errorf("%w: %s", ErrUnknownDefaultTag, defaultTag))
+		}
+	}
+
+	implements := config.Output.Implements
+	implementsNode := mappingValue(output, "implements")
+	if len(implements.Name) > 0 && len(implements.ImportPath) == 0 {
+		v.reportMissing(implementsNode, "importPath")
+	}
+	if len(implements.ImportPath) > 0 && len(implements.Name) == 0 {
+		v.reportMissing(implementsNode, "name")
+	}
+
+	switch policy := config.Output.NoError.Policy; policy {
+	case "", NoErrorPolicyNone, NoErrorPolicyPanic, NoErrorPolicyLastError:
+	default:
+		v.report(mappingValue(mappingValue(output, "noError"), "policy"), fmt.Errorf("%w: unknown policy %q", ErrInvalidNoError, policy))
+	}
+
+	fallback := mappingValue(output, "fallback")
+	switch policy := config.Output.Fallback.Policy; policy {
+	case "", FallbackPolicyNone, FallbackPolicyNearestOlder:
+	default:
+		v.report(mappingValue(fallback, "policy"), fmt.Errorf("%w: unknown policy %q", ErrInvalidFallback, policy))
+	}
+	// the older sources are found by the versions
+	if config.Output.Fallback.Policy == FallbackPolicyNearestOlder {
+		for i, source := range config.Sources {
+			if _, err := semver.Parse(source.Tag); len(source.Tag) > 0 && err != nil {
+				v.report(mappingValue(sequenceItem(sources, i), "tag"), fmt.Errorf("%w: %s policy needs semantic version tags: %v", ErrInvalidFallback, FallbackPolicyNearestOlder, err))
+			}
+		}
+	}
+	// the methods are known after loading the sources: keep their positions
+	config.Output.Fallback.methodPos = make(map[string]configPos)
+	methods := mappingValue(fallback, "methods")
+	for i := 0; methods != nil && i+1 < len(methods.Content); i += 2 {
+		methodName := methods.Content[i].Value
+		config.Output.Fallback.methodPos[methodName] = v.pos(methods.Content[i])
+		for _, tagNode := range methods.Content[i+1].Content {
+			if _, ok := tags[tagNode.Value]; !ok {
+				v.report(tagNode, fmt.Errorf("%w: unknown tag %q for method %s", ErrInvalidFallback, tagNode.Value, methodName))
+			}
+		}
+	}
+
+	rules := mappingValue(output, "rewrite")
+	for i, rule := range config.Output.Rewrite {
+		ruleNode := sequenceItem(rules, i)
+		if err := rule.Compile(); err != nil {
+			v.report(orNode(mappingValue(ruleNode, "match"), ruleNode), fmt.Errorf("%w: %v", ErrInvalidRewrite, err))
+		}
+	}
+}
+
+func (v *configValidator) reportMissing(node *yaml.Node, key string) {
+	v.report(node, fmt.Errorf("%w: %s", ErrMissingConfigField, key))
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func sequenceItem(node *yaml.Node, i int) *yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+		return nil
+	}
+	return node.Content[i]
+}
+
+func orNode(node, fallback *yaml.Node) *yaml.Node {
+	if node != nil {
+		return node
+	}
+	return fallback
+}